@@ -2,15 +2,24 @@ package main
 
 import (
 	"API/src/config"
+	"API/src/controllers"
 	"API/src/routes"
 	"fmt"
 	"log"
 	"net/http"
+
+	// Embute a base de fusos horários para não depender do tzdata do sistema
+	_ "time/tzdata"
 )
 
 func main() {
 	config.Carregar()
 
+	// Sem banco disponível a API sobe mesmo assim, o catálogo é semeado na próxima inicialização
+	if erro := controllers.SemearAlimentos(); erro != nil {
+		log.Printf("erro ao semear catalogo de alimentos: %v", erro)
+	}
+
 	r := routes.Rotear()
 
 	fmt.Printf("Escutando na porta %d", config.PortaAPI)
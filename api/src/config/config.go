@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +15,10 @@ var (
 	StringConexao string
 	PortaAPI      int
 	ChaveSecreta  []byte
+	// JanelaIdempotencia é por quanto tempo a resposta de uma requisição com Idempotency-Key é guardada
+	JanelaIdempotencia time.Duration
+	// MetaAguaPadrao é a meta diária em ml usada nos dias em que o usuário ainda não tinha definido uma meta de água
+	MetaAguaPadrao int
 )
 
 type contextKey string
@@ -36,5 +41,16 @@ func Carregar() {
 
 	ChaveSecreta = []byte(os.Getenv("SECRET_KEY"))
 
+	horasIdempotencia, erro := strconv.Atoi(os.Getenv("IDEMPOTENCY_WINDOW_HOURS"))
+	if erro != nil || horasIdempotencia <= 0 {
+		horasIdempotencia = 24
+	}
+	JanelaIdempotencia = time.Duration(horasIdempotencia) * time.Hour
+
+	MetaAguaPadrao, erro = strconv.Atoi(os.Getenv("DEFAULT_WATER_GOAL_ML"))
+	if erro != nil || MetaAguaPadrao <= 0 {
+		MetaAguaPadrao = 2000
+	}
+
 	StringConexao = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"))
 }
@@ -8,6 +8,7 @@ import (
 	"API/src/responses"
 	"API/src/utils"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
@@ -18,6 +19,18 @@ import (
 
 // CriarConsumoAgua registra um consumo de água do usuário logado
 func CriarConsumoAgua(w http.ResponseWriter, r *http.Request) {
+	criarConsumoAgua(w, r, time.Time{})
+}
+
+// CriarConsumoAguaAgora registra um consumo de água do usuário logado com a data e hora do servidor
+func CriarConsumoAguaAgora(w http.ResponseWriter, r *http.Request) {
+	// O banco guarda até microssegundos
+	criarConsumoAgua(w, r, time.Now().UTC().Truncate(time.Microsecond))
+}
+
+// criarConsumoAgua registra um consumo de água do usuário logado lido da requisição, com o resumo do seu dia e as conquistas.
+// Se data não for zero ela substitui a data enviada pelo cliente
+func criarConsumoAgua(w http.ResponseWriter, r *http.Request, data time.Time) {
 	// Lendo corpo da requisição
 	corpoReq, erro := io.ReadAll(r.Body)
 	if erro != nil {
@@ -31,6 +44,10 @@ func CriarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
 		return
 	}
+	// Com data informada pelo servidor a enviada pelo cliente é ignorada
+	if !data.IsZero() {
+		consumo.Data = data
+	}
 	if erro = consumo.Validar(); erro != nil {
 		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
 		return
@@ -45,8 +62,42 @@ func CriarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer db.Close()
+	// Convertendo recipiente informado em quantidade
+	if consumo.RecipienteID != 0 {
+		recipiente, erro := repositories.BuscarRecipiente(consumo.RecipienteID, matriculaLogado, db)
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+			return
+		}
+		if erro = consumo.AplicarRecipiente(recipiente); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	// Abrindo transação para que o consumo e o resumo do seu dia sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
 	// Chamando repositories para inserir dados no banco de dados
-	if erro = repositories.CriarConsumoAgua(consumo, db); erro != nil {
+	if erro = repositories.CriarConsumoAgua(&consumo, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Atualizando sequência de metas do dia do consumo
+	if erro = atualizarSequenciaAgua(matriculaLogado, []time.Time{consumo.Data}, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Avaliando conquistas do usuário
+	if erro = avaliarConquistas(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
 		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
 		return
 	}
@@ -57,10 +108,9 @@ func CriarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 // BuscarConsumoAgua busca dados de um consumo de água do usuário logado
 func BuscarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 	// Pegando parâremtros da url
-	parametro := chi.URLParam(r, "timestamp")
-	timestamp, erro := time.Parse(time.RFC3339, parametro)
-	if erro != nil {
-		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+	id := chi.URLParam(r, "id")
+	if !utils.EhUUID(id) {
+		responses.RespostaDeErro(w, http.StatusBadRequest, errors.New("id do consumo invalido, formato esperado: uuid"))
 		return
 	}
 	// Extraindo matricula logado do contexto da requisição
@@ -73,7 +123,7 @@ func BuscarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 	// Chamando repositories para bucar dados no banco de dados
-	consumo, erro := repositories.BuscarConsumoAgua(matriculaLogado, timestamp, db)
+	consumo, erro := repositories.BuscarConsumoAgua(matriculaLogado, id, db)
 	if erro != nil {
 		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
 		return
@@ -85,10 +135,9 @@ func BuscarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 // AtualizarConsumoAgua atualiza dados de um consumo de água do usuário logado
 func AtualizarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 	// Pegando parâremtros da url
-	parametro := chi.URLParam(r, "timestamp")
-	timestamp, erro := time.Parse(time.RFC3339, parametro)
-	if erro != nil {
-		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+	id := chi.URLParam(r, "id")
+	if !utils.EhUUID(id) {
+		responses.RespostaDeErro(w, http.StatusBadRequest, errors.New("id do consumo invalido, formato esperado: uuid"))
 		return
 	}
 	// Lendo corpo da requisição
@@ -117,8 +166,48 @@ func AtualizarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer db.Close()
+	// Convertendo recipiente informado em quantidade
+	if consumo.RecipienteID != 0 {
+		recipiente, erro := repositories.BuscarRecipiente(consumo.RecipienteID, matriculaLogado, db)
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+			return
+		}
+		if erro = consumo.AplicarRecipiente(recipiente); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	// Abrindo transação para que o consumo e o resumo do seu dia sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Buscando consumo antes da alteração para saber de que dia ele sai
+	anterior, erro := repositories.BuscarConsumoAgua(matriculaLogado, id, tx)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
 	// Chamando repositories para atualizar dados adcionais no banco de dados
-	if erro = repositories.AtualizarConsumoAgua(matriculaLogado, timestamp, consumo, db); erro != nil {
+	if erro = repositories.AtualizarConsumoAgua(matriculaLogado, id, consumo, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Atualizando sequência de metas dos dias antigo e novo do consumo
+	if erro = atualizarSequenciaAgua(matriculaLogado, []time.Time{anterior.Data, consumo.Data}, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Avaliando conquistas do usuário
+	if erro = avaliarConquistas(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
 		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
 		return
 	}
@@ -129,10 +218,9 @@ func AtualizarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 // DeletarConsumoAgua deleta um consumo de água do usuário logado
 func DeletarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 	// Pegando parâremtros da url
-	parametro := chi.URLParam(r, "timestamp")
-	timestamp, erro := time.Parse(time.RFC3339, parametro)
-	if erro != nil {
-		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+	id := chi.URLParam(r, "id")
+	if !utils.EhUUID(id) {
+		responses.RespostaDeErro(w, http.StatusBadRequest, errors.New("id do consumo invalido, formato esperado: uuid"))
 		return
 	}
 	// Extraindo matricula logado do contexto da requisição
@@ -144,8 +232,31 @@ func DeletarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer db.Close()
-	// Chamando repositories para bucar dados no banco de dados
-	if erro = repositories.DeletarConsumoAgua(matriculaLogado, timestamp, db); erro != nil {
+	// Abrindo transação para que o consumo e o resumo do seu dia sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Buscando consumo antes de deletá-lo para saber de que dia ele sai
+	anterior, erro := repositories.BuscarConsumoAgua(matriculaLogado, id, tx)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Chamando repositories para deletar dados no banco de dados
+	if erro = repositories.DeletarConsumoAgua(matriculaLogado, id, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Atualizando sequência de metas do dia do consumo
+	if erro = atualizarSequenciaAgua(matriculaLogado, []time.Time{anterior.Data}, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
 		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
 		return
 	}
@@ -156,8 +267,8 @@ func DeletarConsumoAgua(w http.ResponseWriter, r *http.Request) {
 // BuscarConsumoAguaDia busca todos consumos de água de um dia do usuário logado
 func BuscarConsumoAguaDia(w http.ResponseWriter, r *http.Request) {
 	// Pegando parâremtros da url
-	dia := chi.URLParam(r, "dia")
-	_, erro := time.Parse("2006-01-02", dia)
+	parametro := chi.URLParam(r, "dia")
+	dia, erro := time.Parse("2006-01-02", parametro)
 	if erro != nil {
 		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
 		return
@@ -171,8 +282,15 @@ func BuscarConsumoAguaDia(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites do período
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio, fim := calendario.Dia(dia)
 	// Chamando repositories para bucar dados no banco de dados
-	consumosDoDia, erro := repositories.BuscarConsumoAguaDia(matriculaLogado, dia, db)
+	consumosDoDia, erro := repositories.BuscarConsumoAguaIntervalo(matriculaLogado, inicio, fim, db)
 	if erro != nil {
 		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
 		return
@@ -204,19 +322,37 @@ func BuscarConsumoAguaMes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites do período
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio, fim := calendario.Mes(mes)
 	// Chamando repositories para bucar dados no banco de dados
-	consumosDoMes, erro := repositories.BuscarConsumoAguaMes(matriculaLogado, mes, db)
+	consumosDoMes, erro := repositories.BuscarConsumoAguaIntervalo(matriculaLogado, inicio, fim, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	aguaAlimentos, erro := repositories.BuscarAguaAlimentosPorDia(matriculaLogado, inicio, fim, calendario, db)
 	if erro != nil {
 		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
 		return
 	}
 	// Caso nenhum registro seja encontrado
-	if len(consumosDoMes) == 0 {
+	if len(consumosDoMes) == 0 && len(aguaAlimentos) == 0 {
 		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
 		return
 	}
+	// Buscando histórico de metas para anotar cada dia com a meta em vigor nele
+	historicoDeMetas, erro := repositories.BuscarMetasAgua(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
 	// Enviando resposta de sucesso
-	responses.RespostaDeSucesso(w, http.StatusOK, consumosDoMes)
+	responses.RespostaDeSucesso(w, http.StatusOK, models.AgruparConsumosPorDia(consumosDoMes, aguaAlimentos, historicoDeMetas, config.MetaAguaPadrao, calendario))
 }
 
 // BuscarConsumoAguaSemana busca todos consumos de água de uma semana do usuário logado
@@ -250,17 +386,415 @@ func BuscarConsumoAguaSemana(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites do período
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio, fim := calendario.Semana(inicioSemana)
+	// Chamando repositories para bucar dados no banco de dados
+	consumosDaSemana, erro := repositories.BuscarConsumoAguaIntervalo(matriculaLogado, inicio, fim, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	aguaAlimentos, erro := repositories.BuscarAguaAlimentosPorDia(matriculaLogado, inicio, fim, calendario, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(consumosDaSemana) == 0 && len(aguaAlimentos) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Buscando histórico de metas para anotar cada dia com a meta em vigor nele
+	historicoDeMetas, erro := repositories.BuscarMetasAgua(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, models.AgruparConsumosPorDia(consumosDaSemana, aguaAlimentos, historicoDeMetas, config.MetaAguaPadrao, calendario))
+}
+
+// BuscarProgressoAgua busca o total consumido em um dia e o compara com a meta do usuário logado
+func BuscarProgressoAgua(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	parametro := chi.URLParam(r, "dia")
+	dia, erro := time.Parse("2006-01-02", parametro)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites do período
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio, fim := calendario.Dia(dia)
+	// Chamando repositories para bucar meta e total consumido no banco de dados
+	aguaMeta, erro := repositories.BuscarAguaMeta(matriculaLogado, parametro, config.MetaAguaPadrao, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	consumido, hidratacao, erro := repositories.BuscarTotalConsumoAguaIntervalo(matriculaLogado, inicio, fim, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	aguaAlimentos, erro := repositories.BuscarAguaAlimentosIntervalo(matriculaLogado, inicio, fim, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	progresso := models.ProgressoAgua{Dia: parametro, Consumido: consumido, Hidratacao: hidratacao + aguaAlimentos, AguaAlimentos: aguaAlimentos, AguaMeta: aguaMeta}
+	progresso.CalcularProgresso()
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, progresso)
+}
+
+// BuscarAgregadoAgua busca totais de consumo de água do usuário logado agrupados por hora, dia, semana ou mês
+func BuscarAgregadoAgua(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	de, erro := time.Parse(time.RFC3339, r.URL.Query().Get("de"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	ate, erro := time.Parse(time.RFC3339, r.URL.Query().Get("ate"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = models.ValidarPeriodo(de, ate); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	bucket := r.URL.Query().Get("bucket")
+	if erro = models.ValidarBucket(bucket); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Agrupado por hora o período é limitado, pois cada hora vira uma linha da resposta
+	if bucket == "hour" {
+		if erro = models.ValidarPeriodoPorHora(de, ate); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Buscando fuso horário do usuário para que os intervalos comecem na hora local
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Chamando repositories para bucar dados no banco de dados
+	agregados, erro := repositories.BuscarAgregadoAgua(matriculaLogado, de, ate, bucket, calendario, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(agregados) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, agregados)
+}
+
+// BuscarConsumosAgua busca consumos de água do usuário logado em um período qualquer, paginados por cursor
+func BuscarConsumosAgua(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	var filtro models.FiltroConsumoAgua
+	var erro error
+	query := r.URL.Query()
+	if de := query.Get("de"); de != "" {
+		if filtro.De, erro = time.Parse(time.RFC3339, de); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	if ate := query.Get("ate"); ate != "" {
+		if filtro.Ate, erro = time.Parse(time.RFC3339, ate); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	if data := query.Get("data"); data != "" {
+		if filtro.Data, erro = time.Parse(time.RFC3339, data); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	if limite := query.Get("limite"); limite != "" {
+		if filtro.Limite, erro = strconv.Atoi(limite); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		if filtro.Cursor, filtro.CursorID, erro = utils.DecodificarCursor(cursor); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	filtro.Ordem = query.Get("ordem")
+	if erro = filtro.Validar(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
 	// Chamando repositories para bucar dados no banco de dados
-	consumosDaSemana, erro := repositories.BuscarConsumoAguaSemana(matriculaLogado, inicioSemana, db)
+	consumos, erro := repositories.BuscarConsumosAguaPeriodo(matriculaLogado, filtro, db)
 	if erro != nil {
 		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
 		return
 	}
 	// Caso nenhum registro seja encontrado
-	if len(consumosDaSemana) == 0 {
+	if len(consumos) == 0 {
 		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
 		return
 	}
+	pagina := models.PaginaConsumoAgua{Consumos: consumos}
+	// Página cheia indica que pode haver mais registros depois do último
+	if len(consumos) == filtro.Limite {
+		ultimo := consumos[len(consumos)-1]
+		pagina.ProximoCursor = utils.CodificarCursor(ultimo.Data, ultimo.ID)
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, pagina)
+}
+
+// BuscarEstatisticasAgua calcula estatísticas dos totais diários de água do usuário logado nos últimos 7, 30 ou 90 dias completos
+func BuscarEstatisticasAgua(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	janela := r.URL.Query().Get("janela")
+	dias, erro := models.DiasDaJanela(janela)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if janela == "" {
+		janela = "7d"
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites dos dias. A janela termina ontem, pois hoje ainda não acabou
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	hoje, erro := time.Parse("2006-01-02", calendario.Hoje())
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio := calendario.InicioDoDia(hoje.Year(), hoje.Month(), hoje.Day()-dias)
+	fim := calendario.InicioDoDia(hoje.Year(), hoje.Month(), hoje.Day())
+	// Chamando repositories para bucar totais diários e metas no banco de dados
+	agregados, erro := repositories.BuscarAgregadoAgua(matriculaLogado, inicio, fim, "day", calendario, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	historicoDeMetas, erro := repositories.BuscarMetasAgua(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Preenchendo todos os dias da janela, inclusive os sem consumo
+	totaisPorDia := make(map[string]models.AgregadoAgua, len(agregados))
+	for _, agregado := range agregados {
+		totaisPorDia[calendario.DiaDe(agregado.Inicio)] = agregado
+	}
+	totais := make([]models.TotalDia, dias)
+	for i := range totais {
+		dia := hoje.AddDate(0, 0, i-dias).Format("2006-01-02")
+		totais[i] = models.TotalDia{Dia: dia, Total: totaisPorDia[dia].Total, Hidratacao: totaisPorDia[dia].TotalHidratacao, AguaMeta: models.MetaAguaDoDia(historicoDeMetas, dia, config.MetaAguaPadrao)}
+	}
+	estatisticas := models.EstatisticasAgua{Janela: janela}
+	estatisticas.CalcularEstatisticas(totais)
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, estatisticas)
+}
+
+// BuscarMapaDeCalorAgua calcula a média de água do usuário logado em cada hora de cada dia da semana de um período
+func BuscarMapaDeCalorAgua(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	de, erro := time.Parse(time.RFC3339, r.URL.Query().Get("de"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	ate, erro := time.Parse(time.RFC3339, r.URL.Query().Get("ate"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = models.ValidarPeriodoPorHora(de, ate); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Buscando fuso horário do usuário para que dias da semana e horas sejam os locais
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Chamando repositories para bucar dados no banco de dados
+	totais, erro := repositories.BuscarTotaisPorHoraAgua(matriculaLogado, de, ate, calendario, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(totais) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	mapaDeCalor := models.MapaDeCalorAgua{De: de, Ate: ate}
+	mapaDeCalor.CalcularMedias(totais, calendario.Local)
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, mapaDeCalor)
+}
+
+// BuscarRecomendacaoAgua calcula a recomendação diária de água do usuário logado, opcionalmente considerando a temperatura ambiente (°C)
+func BuscarRecomendacaoAgua(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	var temperatura *float64
+	if parametro := r.URL.Query().Get("temperatura"); parametro != "" {
+		valor, erro := strconv.ParseFloat(parametro, 64)
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+		if erro = models.ValidarTemperatura(valor); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+		temperatura = &valor
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para buscar dados do usuário logado
+	usuario, erro := repositories.BuscarLogado(matriculaLogado, config.MetaAguaPadrao, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// A idade é calculada no dia de hoje do calendário do usuário
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	hoje, erro := time.Parse("2006-01-02", calendario.Hoje())
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	recomendacao, erro := models.NovaRecomendacaoAgua(usuario, hoje, temperatura)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, recomendacao)
+}
+
+// BuscarEstimulantesDia soma cafeína e álcool consumidos em um dia pelo usuário logado e os compara com seus limites
+func BuscarEstimulantesDia(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	parametro := chi.URLParam(r, "dia")
+	dia, erro := time.Parse("2006-01-02", parametro)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites do período
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio, fim := calendario.Dia(dia)
+	// Chamando repositories para bucar limites e totais no banco de dados
+	limites, erro := repositories.BuscarLimites(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	cafeinaMg, unidadesAlcool, erro := repositories.BuscarEstimulantesIntervalo(matriculaLogado, inicio, fim, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	estimulantes := models.EstimulantesDia{Dia: parametro, CafeinaMg: cafeinaMg, UnidadesAlcool: unidadesAlcool, Limites: limites}
+	estimulantes.VerificarLimites()
 	// Enviando resposta de sucesso
-	responses.RespostaDeSucesso(w, http.StatusOK, consumosDaSemana)
+	responses.RespostaDeSucesso(w, http.StatusOK, estimulantes)
 }
@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/models"
+	"API/src/repositories"
+	"API/src/responses"
+	"API/src/utils"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// BuscarAlimentos busca os alimentos do catálogo, filtrando pelo parâmetro nome se informado
+func BuscarAlimentos(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâmetros da query
+	nome := strings.TrimSpace(r.URL.Query().Get("nome"))
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para bucar dados no banco de dados
+	alimentos, erro := repositories.BuscarAlimentos(nome, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(alimentos) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, alimentos)
+}
+
+// CriarConsumoAlimentos registra uma refeição do usuário logado
+func CriarConsumoAlimentos(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando
+	var consumo models.ConsumoAlimentos
+	if erro = json.Unmarshal(corpoReq, &consumo); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = consumo.Validar(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	consumo.UsuarioMatricula = matriculaLogado
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Abrindo transação para que a refeição e o resumo do seu dia sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Chamando repositories para inserir dados no banco de dados
+	if erro = repositories.CriarConsumoAlimentos(&consumo, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// A água dos alimentos conta na hidratação do dia, então a sequência de metas e as conquistas são atualizadas
+	if erro = atualizarSequenciaAgua(matriculaLogado, []time.Time{consumo.Data}, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	if erro = avaliarConquistas(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusCreated, consumo)
+}
+
+// DeletarConsumoAlimentos deleta uma refeição do usuário logado
+func DeletarConsumoAlimentos(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	parametro := chi.URLParam(r, "timestamp")
+	timestamp, erro := time.Parse(time.RFC3339, parametro)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Abrindo transação para que a refeição e o resumo do seu dia sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Chamando repositories para deletar dados no banco de dados
+	if erro = repositories.DeletarConsumoAlimentos(matriculaLogado, timestamp, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Atualizando sequência de metas do dia da refeição
+	if erro = atualizarSequenciaAgua(matriculaLogado, []time.Time{timestamp}, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+}
+
+// BuscarConsumoAlimentosDia busca todas refeições de um dia do usuário logado
+func BuscarConsumoAlimentosDia(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	dia, erro := time.Parse("2006-01-02", chi.URLParam(r, "dia"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	buscarConsumoAlimentosPeriodo(w, r, func(calendario utils.Calendario) (time.Time, time.Time) {
+		return calendario.Dia(dia)
+	})
+}
+
+// BuscarConsumoAlimentosMes busca todas refeições de um mes do usuário logado
+func BuscarConsumoAlimentosMes(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	mes, erro := time.Parse("2006-01", chi.URLParam(r, "mes"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	buscarConsumoAlimentosPeriodo(w, r, func(calendario utils.Calendario) (time.Time, time.Time) {
+		return calendario.Mes(mes)
+	})
+}
+
+// BuscarConsumoAlimentosSemana busca todas refeições de uma semana do usuário logado
+func BuscarConsumoAlimentosSemana(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	ano, erro := strconv.Atoi(chi.URLParam(r, "ano"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	semana, erro := strconv.Atoi(chi.URLParam(r, "semana"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Calculando o início da semana (segunda-feira)
+	inicioSemana, erro := utils.CalcularInicioDaSemana(ano, semana)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	buscarConsumoAlimentosPeriodo(w, r, func(calendario utils.Calendario) (time.Time, time.Time) {
+		return calendario.Semana(inicioSemana)
+	})
+}
+
+// buscarConsumoAlimentosPeriodo responde com as refeições do usuário logado no período calculado no calendário dele
+func buscarConsumoAlimentosPeriodo(w http.ResponseWriter, r *http.Request, periodo func(utils.Calendario) (time.Time, time.Time)) {
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites do período
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio, fim := periodo(calendario)
+	// Chamando repositories para bucar dados no banco de dados
+	consumos, erro := repositories.BuscarConsumosAlimentosIntervalo(matriculaLogado, inicio, fim, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(consumos) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, consumos)
+}
+
+// SemearAlimentos carrega no banco de dados o catálogo de alimentos do CSV embutido. Chamada na inicialização da API
+func SemearAlimentos() error {
+	alimentos, erro := models.LerAlimentosCSV(bytes.NewReader(database.AlimentosCSV))
+	if erro != nil {
+		return erro
+	}
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		return erro
+	}
+	defer db.Close()
+	return repositories.SemearAlimentos(alimentos, db)
+}
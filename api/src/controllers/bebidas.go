@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/models"
+	"API/src/repositories"
+	"API/src/responses"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// CriarBebida cria uma bebida personalizada para o usuário logado
+func CriarBebida(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando
+	var bebida models.Bebida
+	if erro = json.Unmarshal(corpoReq, &bebida); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = bebida.Validar(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	bebida.UsuarioMatricula = matriculaLogado
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para inserir dados no banco de dados
+	if erro = repositories.CriarBebida(&bebida, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusCreated, bebida)
+}
+
+// BuscarBebidas busca o catálogo de bebidas disponível para o usuário logado
+func BuscarBebidas(w http.ResponseWriter, r *http.Request) {
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para bucar dados no banco de dados
+	bebidas, erro := repositories.BuscarBebidas(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, bebidas)
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/repositories"
+	"API/src/responses"
+	"net/http"
+)
+
+// avaliarConquistas concede ao usuário as conquistas alcançadas depois de uma alteração em seus consumos ou metas.
+// Deve ser chamada depois de atualizar o resumo diário e as sequências, pois as regras dependem deles
+func avaliarConquistas(matricula int, db repositories.Executor) error {
+	return repositories.AvaliarConquistas(matricula, db)
+}
+
+// BuscarConquistas busca as conquistas do usuário logado, as obtidas com a data e as demais com o progresso atual
+func BuscarConquistas(w http.ResponseWriter, r *http.Request) {
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para bucar dados no banco de dados
+	conquistas, erro := repositories.BuscarConquistas(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(conquistas) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, conquistas)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/models"
+	"API/src/repositories"
+	"API/src/responses"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ExportarConsumosAguaFHIR envia os consumos de água do usuário logado como Bundle FHIR R4, com o usuário como Patient e cada consumo como Observation
+func ExportarConsumosAguaFHIR(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	var de, ate time.Time
+	var erro error
+	query := r.URL.Query()
+	if parametro := query.Get("de"); parametro != "" {
+		if de, erro = time.Parse(time.RFC3339, parametro); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	if parametro := query.Get("ate"); parametro != "" {
+		if ate, erro = time.Parse(time.RFC3339, parametro); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	if !de.IsZero() && !ate.IsZero() {
+		if erro = models.ValidarPeriodo(de, ate); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para bucar dados no banco de dados
+	usuario, erro := repositories.BuscarLogado(matriculaLogado, config.MetaAguaPadrao, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	var consumos []models.ConsumoAgua
+	erro = repositories.PercorrerConsumosAgua(matriculaLogado, de, ate, func(consumo models.ConsumoAgua) error {
+		consumos = append(consumos, consumo)
+		return nil
+	}, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Codificando o Bundle antes de enviar, para que uma falha ainda possa virar resposta de erro
+	var bundle bytes.Buffer
+	if erro = json.NewEncoder(&bundle).Encode(models.NovoBundleFHIR(usuario, consumos)); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso com o tipo de conteúdo do FHIR
+	responses.RespostaDeSucessoComTipo(w, http.StatusOK, "application/fhir+json; charset=utf-8", bundle.Bytes())
+}
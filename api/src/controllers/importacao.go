@@ -0,0 +1,396 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/models"
+	"API/src/repositories"
+	"API/src/responses"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// ImportarConsumosAgua registra um lote de consumos de água do usuário logado enviado como array JSON ou NDJSON, com relatório por linha
+func ImportarConsumosAgua(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	opcoes := models.OpcoesLote{Modo: r.URL.Query().Get("modo"), Conflito: r.URL.Query().Get("conflito")}
+	if erro := opcoes.Validar(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Separando as linhas do lote sem interpretá-las, para que um consumo mal formado não invalide os outros
+	var linhas []json.RawMessage
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-ndjson") {
+		for _, linha := range bytes.Split(corpoReq, []byte("\n")) {
+			if linha = bytes.TrimSpace(linha); len(linha) > 0 {
+				linhas = append(linhas, linha)
+			}
+		}
+	} else if erro = json.Unmarshal(corpoReq, &linhas); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if len(linhas) == 0 || len(linhas) > models.MaximoLote {
+		responses.RespostaDeErro(w, http.StatusBadRequest, fmt.Errorf("o lote deve ter entre 1 e %d consumos", models.MaximoLote))
+		return
+	}
+	// Passando cada linha para struct. Erros de leitura entram no relatório da linha
+	consumos := make([]models.ConsumoAgua, len(linhas))
+	errosDeLeitura := make([]error, len(linhas))
+	for i, linha := range linhas {
+		errosDeLeitura[i] = json.Unmarshal(linha, &consumos[i])
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	importarLote(w, matriculaLogado, opcoes, consumos, errosDeLeitura, db)
+}
+
+// importarLote valida consumos lidos de um lote, os insere conforme as opções e envia o relatório por linha.
+// errosDeLeitura tem o erro de conversão de cada linha, se houver. Com quantidade informada o recipiente não a recalcula
+func importarLote(w http.ResponseWriter, matriculaLogado int, opcoes models.OpcoesLote, lidos []models.ConsumoAgua, errosDeLeitura []error, db *sql.DB) {
+	// Bebidas que o usuário pode usar e recipientes já buscados
+	bebidas, erro := repositories.BuscarBebidas(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	bebidasPermitidas := make(map[int]bool, len(bebidas))
+	for _, bebida := range bebidas {
+		bebidasPermitidas[bebida.ID] = true
+	}
+	recipientes := make(map[int]models.Recipiente)
+	// Validando cada linha
+	resultados := make([]models.ResultadoLote, len(lidos))
+	var consumos []models.ConsumoAgua
+	var posicoes []int
+	for i, consumo := range lidos {
+		resultados[i].Linha = i + 1
+		erro := errosDeLeitura[i]
+		if erro == nil {
+			erro = consumo.Validar()
+		}
+		if erro == nil && !bebidasPermitidas[consumo.BebidaID] {
+			erro = errors.New("bebida nao encontrada")
+		}
+		if erro == nil && consumo.RecipienteID != 0 {
+			recipiente, encontrado := recipientes[consumo.RecipienteID]
+			if !encontrado {
+				if recipiente, erro = repositories.BuscarRecipiente(consumo.RecipienteID, matriculaLogado, db); erro == nil {
+					recipientes[consumo.RecipienteID] = recipiente
+				}
+			}
+			if erro == nil {
+				erro = consumo.AplicarRecipiente(recipiente)
+			}
+		}
+		resultados[i].ID = consumo.ID
+		if erro != nil {
+			resultados[i].Status, resultados[i].Erro = models.LinhaComErro, erro.Error()
+			continue
+		}
+		consumo.UsuarioMatricula = matriculaLogado
+		consumos = append(consumos, consumo)
+		posicoes = append(posicoes, i)
+	}
+	// No modo transacao uma linha inválida impede a importação de todo o lote
+	if opcoes.Modo == models.ModoTransacao && len(consumos) < len(lidos) {
+		for _, posicao := range posicoes {
+			resultados[posicao].Status = models.LinhaRevertida
+		}
+		responses.RespostaDeSucesso(w, http.StatusUnprocessableEntity, models.NovoRelatorioLote(resultados))
+		return
+	}
+	// Abrindo transação para que o lote e o resumo dos dias sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Chamando repositories para inserir dados no banco de dados
+	if len(consumos) > 0 {
+		resultadosConsumos, erro := repositories.ImportarConsumosAgua(consumos, opcoes, tx)
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+			return
+		}
+		for j, resultado := range resultadosConsumos {
+			resultado.Linha = posicoes[j] + 1
+			resultados[posicoes[j]] = resultado
+		}
+	}
+	relatorio := models.NovoRelatorioLote(resultados)
+	// Lote desfeito por falha no banco de dados
+	if relatorio.Revertido {
+		responses.RespostaDeSucesso(w, http.StatusUnprocessableEntity, relatorio)
+		return
+	}
+	// Lotes podem alterar muitos dias, então o resumo de todos é refeito de uma vez, seguido das conquistas
+	if relatorio.Criados > 0 || relatorio.Sobrescritos > 0 {
+		if erro = recalcularSequenciaAgua(matriculaLogado, tx); erro != nil {
+			responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+			return
+		}
+		if erro = avaliarConquistas(matriculaLogado, tx); erro != nil {
+			responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+			return
+		}
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, relatorio)
+}
+
+// ImportarConsumosAguaCSV registra consumos de água do usuário logado lidos de um CSV com cabeçalho, com relatório por linha
+func ImportarConsumosAguaCSV(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	query := r.URL.Query()
+	opcoes := models.OpcoesLote{Modo: query.Get("modo"), Conflito: query.Get("conflito")}
+	if erro := opcoes.Validar(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	opcoesCSV := models.OpcoesCSV{FormatoData: query.Get("formato_data"), Unidade: query.Get("unidade"), Separador: query.Get("separador"), Colunas: make(map[string]string)}
+	for _, campo := range models.ColunasCSV {
+		opcoesCSV.Colunas[campo] = query.Get("coluna_" + campo)
+	}
+	if erro := opcoesCSV.Validar(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Datas sem fuso são lidas no fuso horário do usuário
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	opcoesCSV.Local = calendario.Local
+	// Lendo cabeçalho do corpo da requisição
+	defer r.Body.Close()
+	leitor := csv.NewReader(r.Body)
+	leitor.Comma = []rune(opcoesCSV.Separador)[0]
+	leitor.FieldsPerRecord = -1
+	cabecalho, erro := leitor.Read()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, errors.New("cabecalho do csv faltando ou mal formado"))
+		return
+	}
+	indices, erro := opcoesCSV.IndicesDasColunas(cabecalho)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Passando cada linha para struct. Erros de conversão entram no relatório da linha
+	var consumos []models.ConsumoAgua
+	var errosDeLeitura []error
+	for {
+		registro, erro := leitor.Read()
+		if erro == io.EOF {
+			break
+		}
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, fmt.Errorf("linha %d do csv mal formada", len(consumos)+1))
+			return
+		}
+		if len(consumos) == models.MaximoLote {
+			responses.RespostaDeErro(w, http.StatusBadRequest, fmt.Errorf("o lote deve ter entre 1 e %d consumos", models.MaximoLote))
+			return
+		}
+		consumo, erro := opcoesCSV.LerRegistroCSV(registro, indices)
+		consumos = append(consumos, consumo)
+		errosDeLeitura = append(errosDeLeitura, erro)
+	}
+	if len(consumos) == 0 {
+		responses.RespostaDeErro(w, http.StatusBadRequest, fmt.Errorf("o lote deve ter entre 1 e %d consumos", models.MaximoLote))
+		return
+	}
+	importarLote(w, matriculaLogado, opcoes, consumos, errosDeLeitura, db)
+}
+
+// ExportarConsumosAgua envia o histórico de água do usuário logado em CSV, escrevendo as linhas à medida que são lidas do banco de dados
+func ExportarConsumosAgua(w http.ResponseWriter, r *http.Request) {
+	// Pegando e validando parâmetros da query
+	var de, ate time.Time
+	var erro error
+	query := r.URL.Query()
+	if formato := query.Get("formato"); formato != "" && formato != "csv" {
+		responses.RespostaDeErro(w, http.StatusBadRequest, errors.New("formato invalido, valores aceitos: csv"))
+		return
+	}
+	if parametro := query.Get("de"); parametro != "" {
+		if de, erro = time.Parse(time.RFC3339, parametro); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	if parametro := query.Get("ate"); parametro != "" {
+		if ate, erro = time.Parse(time.RFC3339, parametro); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	if !de.IsZero() && !ate.IsZero() {
+		if erro = models.ValidarPeriodo(de, ate); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Datas são exportadas no fuso horário do usuário
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Cabeçalhos só são enviados quando a consulta já retornou, para que um erro nela ainda possa ser respondido como JSON
+	escritor := csv.NewWriter(w)
+	iniciado := false
+	iniciar := func() {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="historico_de_agua.csv"`)
+		w.WriteHeader(http.StatusOK)
+		escritor.Write(models.CabecalhoExportacaoCSV())
+		iniciado = true
+	}
+	linhas := 0
+	erro = repositories.PercorrerConsumosAgua(matriculaLogado, de, ate, func(consumo models.ConsumoAgua) error {
+		if !iniciado {
+			iniciar()
+		}
+		if erro := escritor.Write(consumo.RegistroCSV(calendario.Local)); erro != nil {
+			return erro
+		}
+		// Enviando as linhas em partes para não acumular o arquivo inteiro na memória
+		if linhas++; linhas%500 == 0 {
+			escritor.Flush()
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+		return escritor.Error()
+	}, db)
+	if erro != nil && !iniciado {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Histórico vazio é exportado só com o cabeçalho. Erros depois do início do envio apenas interrompem o arquivo
+	if !iniciado {
+		iniciar()
+	}
+	escritor.Flush()
+}
+
+// ImportarConsumosAguaSaude registra consumos de água do usuário logado lidos de um arquivo exportado pelo Apple Health (export.xml)
+// ou pelo Google Fit (JSON do Takeout), ignorando consumos já importados ou já registrados com mesma data e quantidade
+func ImportarConsumosAguaSaude(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâmetros da url
+	origem := strings.ReplaceAll(chi.URLParam(r, "origem"), "-", "_")
+	if erro := models.ValidarOrigem(origem); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Lendo arquivo enviado no corpo ou no campo arquivo de um formulário
+	defer r.Body.Close()
+	arquivo, erro := lerArquivoEnviado(r)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Passando registros de água do arquivo para structs
+	var consumos []models.ConsumoAgua
+	var errosDeLeitura []error
+	if origem == models.OrigemAppleHealth {
+		consumos, errosDeLeitura, erro = models.LerAppleHealth(arquivo, matriculaLogado)
+	} else {
+		consumos, errosDeLeitura, erro = models.LerGoogleFit(arquivo, matriculaLogado)
+	}
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if len(consumos) == 0 {
+		responses.RespostaDeErro(w, http.StatusBadRequest, errors.New("nenhum registro de agua encontrado no arquivo"))
+		return
+	}
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Registros inválidos não impedem os demais e registros repetidos são ignorados
+	opcoes := models.OpcoesLote{Modo: models.ModoParcial, Conflito: models.ConflitoPular, IgnorarDuplicados: true}
+	importarLote(w, matriculaLogado, opcoes, consumos, errosDeLeitura, db)
+}
+
+// lerArquivoEnviado retorna o campo arquivo de uma requisição multipart/form-data ou, nos demais casos, o próprio corpo
+func lerArquivoEnviado(r *http.Request) (io.Reader, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, nil
+	}
+	leitor, erro := r.MultipartReader()
+	if erro != nil {
+		return nil, erro
+	}
+	// Lendo partes do formulário sem guardá-las até encontrar o arquivo
+	for {
+		parte, erro := leitor.NextPart()
+		if erro == io.EOF {
+			return nil, errors.New("campo arquivo faltando no formulario")
+		}
+		if erro != nil {
+			return nil, erro
+		}
+		if parte.FormName() == "arquivo" {
+			return parte, nil
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/models"
+	"API/src/repositories"
+	"API/src/responses"
+	"API/src/utils"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// CriarMedida registra uma medida corporal do usuário logado
+func CriarMedida(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando
+	var medida models.Medida
+	if erro = json.Unmarshal(corpoReq, &medida); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = medida.Validar(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	medida.Dono = matriculaLogado
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para inserir dados no banco de dados
+	if erro = repositories.CriarMedida(medida, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Peso da medida passa a ser o peso do usuário se ela for a mais recente
+	if erro = repositories.SincronizarPeso(matriculaLogado, medida.Data, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusCreated, medida)
+}
+
+// BuscarMedidas busca todas medidas do usuário logado
+func BuscarMedidas(w http.ResponseWriter, r *http.Request) {
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para bucar dados no banco de dados
+	medidas, erro := repositories.BuscarMedidas(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(medidas) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, medidas)
+}
+
+// BuscarMedida busca uma medida do usuário logado pela data e hora
+func BuscarMedida(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	parametro := chi.URLParam(r, "timestamp")
+	timestamp, erro := time.Parse(time.RFC3339, parametro)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para bucar dados no banco de dados
+	medida, erro := repositories.BuscarMedida(matriculaLogado, timestamp, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, medida)
+}
+
+// AtualizarMedida atualiza os valores de uma medida do usuário logado
+func AtualizarMedida(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	parametro := chi.URLParam(r, "timestamp")
+	timestamp, erro := time.Parse(time.RFC3339, parametro)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando
+	var medida models.Medida
+	if erro = json.Unmarshal(corpoReq, &medida); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = medida.ValidarMedidas(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para atualizar dados no banco de dados
+	if erro = repositories.AtualizarMedida(matriculaLogado, timestamp, medida, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// A medida alterada pode ser a mais recente
+	if erro = repositories.SincronizarPeso(matriculaLogado, timestamp, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+}
+
+// DeletarMedida deleta uma medida do usuário logado
+func DeletarMedida(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	parametro := chi.URLParam(r, "timestamp")
+	timestamp, erro := time.Parse(time.RFC3339, parametro)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para deletar dados no banco de dados
+	if erro = repositories.DeletarMedida(matriculaLogado, timestamp, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Se o peso do usuário veio dessa medida ele volta para o da mais recente que sobrou, se houver
+	if erro = repositories.SincronizarPeso(matriculaLogado, timestamp, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+}
+
+// BuscarMedidasDia busca todas medidas de um dia do usuário logado
+func BuscarMedidasDia(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	dia, erro := time.Parse("2006-01-02", chi.URLParam(r, "dia"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	buscarMedidasPeriodo(w, r, func(calendario utils.Calendario) (time.Time, time.Time) {
+		return calendario.Dia(dia)
+	})
+}
+
+// BuscarMedidasMes busca todas medidas de um mes do usuário logado
+func BuscarMedidasMes(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	mes, erro := time.Parse("2006-01", chi.URLParam(r, "mes"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	buscarMedidasPeriodo(w, r, func(calendario utils.Calendario) (time.Time, time.Time) {
+		return calendario.Mes(mes)
+	})
+}
+
+// BuscarMedidasSemana busca todas medidas de uma semana do usuário logado
+func BuscarMedidasSemana(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	ano, erro := strconv.Atoi(chi.URLParam(r, "ano"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	semana, erro := strconv.Atoi(chi.URLParam(r, "semana"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Calculando o início da semana (segunda-feira)
+	inicioSemana, erro := utils.CalcularInicioDaSemana(ano, semana)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	buscarMedidasPeriodo(w, r, func(calendario utils.Calendario) (time.Time, time.Time) {
+		return calendario.Semana(inicioSemana)
+	})
+}
+
+// buscarMedidasPeriodo responde com as medidas do usuário logado no período calculado no calendário dele
+func buscarMedidasPeriodo(w http.ResponseWriter, r *http.Request, periodo func(utils.Calendario) (time.Time, time.Time)) {
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites do período
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio, fim := periodo(calendario)
+	// Chamando repositories para bucar dados no banco de dados
+	medidas, erro := repositories.BuscarMedidasIntervalo(matriculaLogado, inicio, fim, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(medidas) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, medidas)
+}
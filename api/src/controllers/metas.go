@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/models"
+	"API/src/repositories"
+	"API/src/responses"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// CriarMetaAgua registra uma meta de água do usuário logado válida a partir de um dia
+func CriarMetaAgua(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando
+	var meta models.MetaAgua
+	if erro = json.Unmarshal(corpoReq, &meta); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Sem data de início a meta passa a valer hoje no fuso do usuário
+	if meta.ValidaDesde == "" {
+		calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+			return
+		}
+		meta.ValidaDesde = calendario.Hoje()
+	}
+	if erro = meta.Validar(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Abrindo transação para que a meta e o resumo dos dias sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Chamando repositories para inserir dados no banco de dados
+	if erro = repositories.CriarMetaAgua(matriculaLogado, meta, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Metas mudam quais dias foram atingidos e com isso as sequências
+	if erro = repositories.AtualizarMetasDiasAgua(matriculaLogado, config.MetaAguaPadrao, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Novas metas e dias podem liberar conquistas
+	if erro = avaliarConquistas(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusCreated, meta)
+}
+
+// BuscarMetasAgua busca o histórico de metas de água do usuário logado
+func BuscarMetasAgua(w http.ResponseWriter, r *http.Request) {
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para bucar dados no banco de dados
+	metas, erro := repositories.BuscarMetasAgua(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum registro seja encontrado
+	if len(metas) == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, metas)
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/repositories"
+	"API/src/responses"
+	"API/src/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+// GerarRelatorioMensal calcula as médias diárias de kcal, macronutrientes e água de um mês do usuário logado
+func GerarRelatorioMensal(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	mes, erro := time.Parse("2006-01", chi.URLParam(r, "mes"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	gerarRelatorio(w, r, func(calendario utils.Calendario) (time.Time, time.Time) {
+		return calendario.Mes(mes)
+	})
+}
+
+// GerarRelatorioSemanal calcula as médias diárias de kcal, macronutrientes e água de uma semana do usuário logado
+func GerarRelatorioSemanal(w http.ResponseWriter, r *http.Request) {
+	// Pegando parâremtros da url
+	ano, erro := strconv.Atoi(chi.URLParam(r, "ano"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	semana, erro := strconv.Atoi(chi.URLParam(r, "semana"))
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Calculando o início da semana (segunda-feira)
+	inicioSemana, erro := utils.CalcularInicioDaSemana(ano, semana)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	gerarRelatorio(w, r, func(calendario utils.Calendario) (time.Time, time.Time) {
+		return calendario.Semana(inicioSemana)
+	})
+}
+
+// gerarRelatorio responde com o relatório do usuário logado no período calculado no calendário dele
+func gerarRelatorio(w http.ResponseWriter, r *http.Request, periodo func(utils.Calendario) (time.Time, time.Time)) {
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Buscando fuso horário do usuário para calcular os limites do período e de cada dia
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	inicio, fim := periodo(calendario)
+	// Chamando repositories para calcular as médias no banco de dados
+	relatorio, erro := repositories.BuscarRelatorio(matriculaLogado, inicio, fim, calendario, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Caso nenhum consumo seja encontrado no período
+	if relatorio.Dias == 0 {
+		responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, relatorio)
+}
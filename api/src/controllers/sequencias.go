@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"API/src/config"
+	"API/src/database"
+	"API/src/repositories"
+	"API/src/responses"
+	"net/http"
+	"time"
+)
+
+// atualizarSequenciaAgua atualiza o resumo dos dias do usuário alterados por consumos feitos nos instantes informados, e com eles suas sequências
+func atualizarSequenciaAgua(matricula int, instantes []time.Time, db repositories.Executor) error {
+	calendario, erro := repositories.BuscarCalendario(matricula, db)
+	if erro != nil {
+		return erro
+	}
+	atualizados := make(map[string]bool)
+	for _, instante := range instantes {
+		dia := calendario.DiaDe(instante)
+		if atualizados[dia] {
+			continue
+		}
+		if erro = repositories.AtualizarDiaMetaAgua(matricula, dia, calendario, config.MetaAguaPadrao, db); erro != nil {
+			return erro
+		}
+		atualizados[dia] = true
+	}
+	return nil
+}
+
+// recalcularSequenciaAgua refaz o resumo de todos os dias do usuário e suas sequências
+func recalcularSequenciaAgua(matricula int, db repositories.Executor) error {
+	calendario, erro := repositories.BuscarCalendario(matricula, db)
+	if erro != nil {
+		return erro
+	}
+	return repositories.RecalcularDiasMetaAgua(matricula, calendario, config.MetaAguaPadrao, db)
+}
+
+// BuscarSequenciaAgua busca a sequência atual e a maior sequência de dias seguidos em que o usuário logado atingiu a meta de água
+func BuscarSequenciaAgua(w http.ResponseWriter, r *http.Request) {
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para bucar dados no banco de dados
+	sequencia, erro := repositories.BuscarSequenciaAgua(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// A sequência guardada só é a atual se terminou hoje ou ontem no calendário do usuário
+	calendario, erro := repositories.BuscarCalendario(matriculaLogado, db)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	sequencia.CalcularAtual(calendario.Hoje())
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusOK, sequencia)
+}
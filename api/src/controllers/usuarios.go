@@ -60,7 +60,7 @@ func BuscarLogado(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 	// Chamando repositories para buscar dados do usuário logado
-	dados, erro := repositories.BuscarLogado(matriculaLogado, db)
+	dados, erro := repositories.BuscarLogado(matriculaLogado, config.MetaAguaPadrao, db)
 	if erro != nil {
 		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
 		return
@@ -236,3 +236,256 @@ func AtualizarSenha(w http.ResponseWriter, r *http.Request) {
 	// Enviando resposta de sucesso
 	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
 }
+
+// AtualizarMetas atualiza metas de um usuário
+func AtualizarMetas(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando dados
+	var metas models.Metas
+	if erro = json.Unmarshal(corpoReq, &metas); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = metas.ValidarMetas(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Abrindo transação para que a meta e o resumo dos dias sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Chamando repositories para atualizar metas no banco de dados
+	if erro = repositories.AtualizarMetas(metas, matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Metas mudam quais dias foram atingidos e com isso as sequências
+	if erro = repositories.AtualizarMetasDiasAgua(matriculaLogado, config.MetaAguaPadrao, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Novas metas e dias podem liberar conquistas
+	if erro = avaliarConquistas(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+}
+
+// AtualizarFusoHorario atualiza fuso horário de um usuário
+func AtualizarFusoHorario(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando dados
+	var fusoHorario models.Usuario
+	if erro = json.Unmarshal(corpoReq, &fusoHorario); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = fusoHorario.ValidarFusoHorario(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	fusoHorario.Matricula = matriculaLogado
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Abrindo transação para que a alteração e o resumo dos dias sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Chamando repositories para atualizar dados no banco de dados
+	if erro = repositories.AtualizarFusoHorario(fusoHorario, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Limites dos dias mudaram, então o resumo diário e as sequências são refeitos
+	if erro = recalcularSequenciaAgua(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Novas metas e dias podem liberar conquistas
+	if erro = avaliarConquistas(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+}
+
+// AtualizarDadosAdicionais atualiza altura, peso, frequência de atividade física e horas de acordar e dormir do usuário logado
+func AtualizarDadosAdicionais(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando dados
+	var dados models.DadosAdicionais
+	if erro = json.Unmarshal(corpoReq, &dados); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = dados.ValidarDadosAdicionais(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para atualizar dados no banco de dados
+	if erro = repositories.AtualizarDadosAdicionais(dados, matriculaLogado, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+}
+
+// AtualizarHoraInicioDia atualiza a hora em que o dia de um usuário começa
+func AtualizarHoraInicioDia(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando dados
+	var horaInicioDia models.Usuario
+	if erro = json.Unmarshal(corpoReq, &horaInicioDia); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = horaInicioDia.ValidarHoraInicioDia(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	horaInicioDia.Matricula = matriculaLogado
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Abrindo transação para que a alteração e o resumo dos dias sejam gravados juntos
+	tx, erro := db.Begin()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer tx.Rollback()
+	// Chamando repositories para atualizar dados no banco de dados
+	if erro = repositories.AtualizarHoraInicioDia(horaInicioDia, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Limites dos dias mudaram, então o resumo diário e as sequências são refeitos
+	if erro = recalcularSequenciaAgua(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Novas metas e dias podem liberar conquistas
+	if erro = avaliarConquistas(matriculaLogado, tx); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Confirmando transação
+	if erro = tx.Commit(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+}
+
+// AtualizarLimites atualiza limites diários de cafeína e álcool de um usuário
+func AtualizarLimites(w http.ResponseWriter, r *http.Request) {
+	// Lendo corpo da requisição
+	corpoReq, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+		return
+	}
+	defer r.Body.Close()
+	// Passando para struct e validando dados
+	var limites models.Limites
+	if erro = json.Unmarshal(corpoReq, &limites); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	if erro = limites.ValidarLimites(); erro != nil {
+		responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+		return
+	}
+	// Extraindo matricula logado do contexto da requisição
+	matriculaLogado := r.Context().Value(config.MatriculaKey).(int)
+	// Abrindo conexão com banco de dados
+	db, erro := database.ConectarDB()
+	if erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	defer db.Close()
+	// Chamando repositories para atualizar limites no banco de dados
+	if erro = repositories.AtualizarLimites(limites, matriculaLogado, db); erro != nil {
+		responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+		return
+	}
+	// Enviando resposta de sucesso
+	responses.RespostaDeSucesso(w, http.StatusNoContent, nil)
+}
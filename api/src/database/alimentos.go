@@ -0,0 +1,9 @@
+package database
+
+import _ "embed"
+
+// AlimentosCSV é o catálogo inicial de alimentos (valores por 100 g, aproximados da tabela TACO), embutido no binário
+// para não depender de arquivos no diretório de execução. Colunas: nome, kcal, proteina, carboidrato, gordura e agua (g)
+//
+//go:embed alimentos.csv
+var AlimentosCSV []byte
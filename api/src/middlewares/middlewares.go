@@ -4,9 +4,16 @@ import (
 	"API/src/auth"
 	"API/src/config"
 	"API/src/database"
+	"API/src/models"
 	"API/src/repositories"
 	"API/src/responses"
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
 	"net/http"
 )
 
@@ -47,3 +54,103 @@ func Autenticar(proximaFunc http.Handler) http.Handler {
 		proximaFunc.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// gravadorDeResposta repassa a resposta ao cliente guardando status e corpo enviados
+type gravadorDeResposta struct {
+	http.ResponseWriter
+	statusCode int
+	corpo      bytes.Buffer
+}
+
+func (g *gravadorDeResposta) WriteHeader(statusCode int) {
+	g.statusCode = statusCode
+	g.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (g *gravadorDeResposta) Write(dados []byte) (int, error) {
+	if g.statusCode == 0 {
+		g.statusCode = http.StatusOK
+	}
+	g.corpo.Write(dados)
+	return g.ResponseWriter.Write(dados)
+}
+
+// Idempotencia guarda a primeira resposta de requisições com cabeçalho Idempotency-Key por usuário e chave e a repete nas retentativas.
+// Sem usuário logado (cadastro) a chave fica associada à matrícula 0 junto com o hash da requisição, para que clientes diferentes
+// não compartilhem chaves. Deve ser usado depois de Autenticar nas rotas autenticadas
+func Idempotencia(proximaFunc http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chave := r.Header.Get("Idempotency-Key")
+		if chave == "" {
+			proximaFunc.ServeHTTP(w, r)
+			return
+		}
+		// Lendo corpo da requisição e o devolvendo para o controller
+		corpoReq, erro := io.ReadAll(r.Body)
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusUnprocessableEntity, erro)
+			return
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(corpoReq))
+		// Mesma chave só pode ser reaproveitada com a mesma requisição. O hash é um HMAC com a chave secreta da API,
+		// para que o corpo guardado (que no cadastro tem a senha) não possa ser descoberto por força bruta a partir do banco
+		hash := hmac.New(sha256.New, config.ChaveSecreta)
+		hash.Write([]byte(r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery + "\n"))
+		hash.Write(corpoReq)
+		matriculaLogado, _ := r.Context().Value(config.MatriculaKey).(int)
+		requisicao := models.RequisicaoIdempotente{UsuarioMatricula: matriculaLogado, Chave: chave, HashRequisicao: hex.EncodeToString(hash.Sum(nil))}
+		if erro = requisicao.Validar(); erro != nil {
+			responses.RespostaDeErro(w, http.StatusBadRequest, erro)
+			return
+		}
+		hashRequisicao := requisicao.HashRequisicao
+		// Sem usuário logado só a mesma requisição com a mesma chave é repetida
+		if matriculaLogado == 0 {
+			chaveAnonima := hmac.New(sha256.New, config.ChaveSecreta)
+			chaveAnonima.Write([]byte(hashRequisicao + chave))
+			requisicao.Chave = hex.EncodeToString(chaveAnonima.Sum(nil))
+		}
+		// Abrindo conexão com banco de dados
+		db, erro := database.ConectarDB()
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+			return
+		}
+		defer db.Close()
+		reservada, erro := repositories.ReservarChaveIdempotencia(&requisicao, config.JanelaIdempotencia, db)
+		if erro != nil {
+			responses.RespostaDeErro(w, http.StatusInternalServerError, erro)
+			return
+		}
+		if !reservada {
+			if requisicao.HashRequisicao != hashRequisicao {
+				responses.RespostaDeErro(w, http.StatusUnprocessableEntity, errors.New("Idempotency-Key ja usada em outra requisicao"))
+				return
+			}
+			if requisicao.StatusCode == 0 {
+				responses.RespostaDeErro(w, http.StatusConflict, errors.New("requisicao com essa Idempotency-Key ainda esta sendo processada"))
+				return
+			}
+			// Repetindo a resposta guardada
+			w.Header().Set("Idempotent-Replayed", "true")
+			if len(requisicao.Corpo) > 0 {
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			}
+			w.WriteHeader(requisicao.StatusCode)
+			w.Write(requisicao.Corpo)
+			return
+		}
+		gravador := &gravadorDeResposta{ResponseWriter: w}
+		proximaFunc.ServeHTTP(gravador, r)
+		// Erros do servidor não são guardados para que a retentativa possa ter sucesso.
+		// A resposta já foi enviada, então falhas ao guardar ou liberar a chave são ignoradas
+		if gravador.statusCode == 0 || gravador.statusCode >= http.StatusInternalServerError {
+			repositories.LiberarChaveIdempotencia(requisicao.UsuarioMatricula, requisicao.Chave, db)
+			return
+		}
+		requisicao.StatusCode = gravador.statusCode
+		requisicao.Corpo = gravador.corpo.Bytes()
+		repositories.SalvarRespostaIdempotencia(requisicao, db)
+	})
+}
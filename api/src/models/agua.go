@@ -1,23 +1,212 @@
 package models
 
 import (
+	"API/src/utils"
 	"errors"
+	"math"
+	"sort"
 	"time"
 )
 
 type ConsumoAgua struct {
+	ID               string    `json:"id,omitempty"` // UUID gerado pelo cliente ou pelo servidor
 	UsuarioMatricula int       `json:"usuario_matricula,omitempty"`
 	Data             time.Time `json:"data,omitempty"` //yyyy-mm-ddThh:mm:ssZ
 	Quantidade       int       `json:"quantidade,omitempty"`
+	BebidaID         int       `json:"bebida_id,omitempty"`
+	Hidratacao       int       `json:"hidratacao"` // quantidade multiplicada pelo fator de hidratação da bebida
+	CafeinaMg        int       `json:"cafeina_mg,omitempty"`
+	UnidadesAlcool   float64   `json:"unidades_alcool,omitempty"`
+	RecipienteID     int       `json:"recipiente_id,omitempty"`
+	Fracao           float64   `json:"fracao,omitempty"` // fração do recipiente consumida, usada só para calcular a quantidade
+	Origem           string    `json:"origem,omitempty"` // aplicativo de onde o consumo foi importado, vazio quando registrado na API
 }
 
-// Validar verifica se o campo data está presente e se a quantidade de água e porcentagem da meta foi maior que 0
-func (c ConsumoAgua) Validar() error {
+// Validar verifica se o campo data está presente e se a quantidade de água e porcentagem da meta foi maior que 0. Sem bebida o consumo é de água
+// e com recipiente a quantidade pode ser omitida, pois é calculada depois por AplicarRecipiente
+func (c *ConsumoAgua) Validar() error {
+	if c.ID != "" && !utils.EhUUID(c.ID) {
+		return errors.New("id do consumo invalido, formato esperado: uuid")
+	}
 	if c.Data.IsZero() {
 		return errors.New("data e hora do consumo faltando")
 	}
+	if c.RecipienteID == 0 && c.Quantidade == 0 {
+		return errors.New("a quantidade de agua nao podem ser 0")
+	}
+	if c.Fracao < 0 || c.Fracao > 10 {
+		return errors.New("a fracao do recipiente deve estar entre 0 e 10")
+	}
+	if c.BebidaID == 0 {
+		c.BebidaID = BebidaAgua
+	}
+	if c.CafeinaMg < 0 || c.UnidadesAlcool < 0 {
+		return errors.New("cafeina e unidades de alcool nao podem ser negativas")
+	}
+	if c.UnidadesAlcool > MaximoUnidadesAlcool {
+		return errors.New("unidades de alcool devem ser no maximo 100")
+	}
+	if len(c.Origem) > 20 {
+		return errors.New("origem do consumo deve ter no maximo 20 caracteres")
+	}
+	return nil
+}
+
+// AplicarRecipiente calcula a quantidade consumida a partir da capacidade do recipiente e da fração consumida (padrão 1).
+// Quantidade informada explicitamente prevalece sobre o recipiente e não é recalculada
+func (c *ConsumoAgua) AplicarRecipiente(recipiente Recipiente) error {
+	if c.Quantidade != 0 {
+		return nil
+	}
+	if c.Fracao == 0 {
+		c.Fracao = 1
+	}
+	c.Quantidade = int(math.Round(float64(recipiente.Capacidade) * c.Fracao))
 	if c.Quantidade == 0 {
 		return errors.New("a quantidade de agua nao podem ser 0")
 	}
 	return nil
 }
+
+type ProgressoAgua struct {
+	Dia           string  `json:"dia"`
+	Consumido     int     `json:"consumido"`
+	Hidratacao    int     `json:"hidratacao"`
+	AguaAlimentos int     `json:"agua_alimentos"` // água contida nos alimentos consumidos, já somada na hidratação
+	AguaMeta      int     `json:"agua_meta"`
+	Porcentagem   float64 `json:"porcentagem"`
+	Restante      int     `json:"restante"`
+}
+
+// CalcularProgresso preenche porcentagem da meta atingida pela hidratação efetiva e quantidade restante para atingi-la
+func (p *ProgressoAgua) CalcularProgresso() {
+	if p.AguaMeta <= 0 {
+		// Usuário com meta 0
+		p.Porcentagem = 0
+		p.Restante = 0
+		return
+	}
+	p.Porcentagem = math.Round(float64(p.Hidratacao)*10000/float64(p.AguaMeta)) / 100
+	p.Restante = max(p.AguaMeta-p.Hidratacao, 0)
+}
+
+type ConsumoAguaDia struct {
+	Dia             string        `json:"dia"`
+	Total           int           `json:"total"`
+	TotalHidratacao int           `json:"total_hidratacao"`
+	AguaAlimentos   int           `json:"agua_alimentos"` // água contida nos alimentos consumidos, já somada na hidratação
+	AguaMeta        int           `json:"agua_meta"`
+	MetaAtingida    bool          `json:"meta_atingida"`
+	Consumos        []ConsumoAgua `json:"consumos"`
+}
+
+// AgruparConsumosPorDia agrupa consumos ordenados por data em dias do usuário, somando a água dos alimentos de cada dia (yyyy-mm-dd)
+// e anotando cada dia com a meta em vigor nele, ou a padrão. Dias só com alimentos também aparecem, sem consumos
+func AgruparConsumosPorDia(consumos []ConsumoAgua, aguaAlimentos map[string]int, historicoDeMetas []MetaAgua, metaPadrao int, calendario utils.Calendario) []ConsumoAguaDia {
+	var dias []ConsumoAguaDia
+	for _, consumo := range consumos {
+		dia := calendario.DiaDe(consumo.Data)
+		if len(dias) == 0 || dias[len(dias)-1].Dia != dia {
+			dias = append(dias, ConsumoAguaDia{Dia: dia})
+		}
+		atual := &dias[len(dias)-1]
+		atual.Total += consumo.Quantidade
+		atual.TotalHidratacao += consumo.Hidratacao
+		atual.Consumos = append(atual.Consumos, consumo)
+	}
+	comConsumos := make(map[string]bool, len(dias))
+	for _, dia := range dias {
+		comConsumos[dia.Dia] = true
+	}
+	for dia := range aguaAlimentos {
+		if !comConsumos[dia] {
+			dias = append(dias, ConsumoAguaDia{Dia: dia, Consumos: []ConsumoAgua{}})
+		}
+	}
+	sort.Slice(dias, func(i, j int) bool { return dias[i].Dia < dias[j].Dia })
+	for i := range dias {
+		dias[i].AguaAlimentos = aguaAlimentos[dias[i].Dia]
+		dias[i].TotalHidratacao += dias[i].AguaAlimentos
+		dias[i].AguaMeta = MetaAguaDoDia(historicoDeMetas, dias[i].Dia, metaPadrao)
+		dias[i].MetaAtingida = dias[i].AguaMeta > 0 && dias[i].TotalHidratacao >= dias[i].AguaMeta
+	}
+	return dias
+}
+
+type AgregadoAgua struct {
+	Inicio          time.Time `json:"inicio"`
+	Total           int       `json:"total"`
+	TotalHidratacao int       `json:"total_hidratacao"`
+	AguaAlimentos   int       `json:"agua_alimentos"` // água contida nos alimentos consumidos, já somada na hidratação
+	Consumos        int       `json:"consumos"`
+	MenorConsumo    int       `json:"menor_consumo"`
+	MaiorConsumo    int       `json:"maior_consumo"`
+}
+
+// ValidarBucket verifica se o tamanho do intervalo de agregação é um dos aceitos pelo date_trunc
+func ValidarBucket(bucket string) error {
+	switch bucket {
+	case "hour", "day", "week", "month":
+		return nil
+	}
+	return errors.New("bucket invalido, valores aceitos: hour, day, week e month")
+}
+
+// ValidarPeriodo verifica se o início de um período vem antes do seu fim
+func ValidarPeriodo(de, ate time.Time) error {
+	if !de.Before(ate) {
+		return errors.New("o inicio do periodo (de) deve ser anterior ao fim (ate)")
+	}
+	return nil
+}
+
+// PeriodoMaximoPorHora é o maior período aceito nas consultas que percorrem ou agrupam consumos hora a hora
+const PeriodoMaximoPorHora = 366 * 24 * time.Hour
+
+// ValidarPeriodoPorHora verifica um período consultado hora a hora, que além de válido não pode passar de 366 dias
+func ValidarPeriodoPorHora(de, ate time.Time) error {
+	if erro := ValidarPeriodo(de, ate); erro != nil {
+		return erro
+	}
+	if ate.Sub(de) > PeriodoMaximoPorHora {
+		return errors.New("o periodo consultado por hora deve ter no maximo 366 dias")
+	}
+	return nil
+}
+
+type FiltroConsumoAgua struct {
+	De       time.Time
+	Ate      time.Time
+	Data     time.Time // busca apenas consumos nesse instante exato
+	Limite   int
+	Cursor   time.Time
+	CursorID string
+	Ordem    string
+}
+
+type PaginaConsumoAgua struct {
+	Consumos      []ConsumoAgua `json:"consumos"`
+	ProximoCursor string        `json:"proximo_cursor,omitempty"`
+}
+
+// Validar verifica período, limite e ordem de uma listagem de consumos, preenchendo limite e ordem padrões
+func (f *FiltroConsumoAgua) Validar() error {
+	if !f.De.IsZero() && !f.Ate.IsZero() {
+		if erro := ValidarPeriodo(f.De, f.Ate); erro != nil {
+			return erro
+		}
+	}
+	if f.Limite == 0 {
+		f.Limite = 50
+	}
+	if f.Limite < 0 || f.Limite > 500 {
+		return errors.New("limite deve estar entre 1 e 500")
+	}
+	if f.Ordem == "" {
+		f.Ordem = "asc"
+	}
+	if f.Ordem != "asc" && f.Ordem != "desc" {
+		return errors.New("ordem invalida, valores aceitos: asc e desc")
+	}
+	return nil
+}
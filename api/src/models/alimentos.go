@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// colunasAlimentosCSV são as colunas do CSV do catálogo de alimentos, nessa ordem
+var colunasAlimentosCSV = []string{"nome", "kcal", "proteina", "carboidrato", "gordura", "agua"}
+
+// Alimento guarda os valores nutricionais por 100 g. Só existem alimentos simples no catálogo, compostos (receitas) ainda não são suportados
+type Alimento struct {
+	ID          int     `json:"id"`
+	Nome        string  `json:"nome"`
+	EhSimples   int     `json:"eh_simples"`
+	Kcal        int     `json:"kcal"`
+	Proteina    float64 `json:"proteina"`
+	Carboidrato float64 `json:"carboidrato"`
+	Gordura     float64 `json:"gordura"`
+	Agua        float64 `json:"agua"` // gramas de água por 100 g
+}
+
+// Validar verifica nome e se os valores por 100 g são possíveis
+func (a *Alimento) Validar() error {
+	a.Nome = strings.TrimSpace(a.Nome)
+	if a.Nome == "" || len(a.Nome) > 100 {
+		return errors.New("nome do alimento deve ter entre 1 e 100 caracteres")
+	}
+	if a.Kcal < 0 || a.Kcal > 900 {
+		return errors.New("kcal deve estar entre 0 e 900 por 100 g")
+	}
+	for _, valor := range []float64{a.Proteina, a.Carboidrato, a.Gordura, a.Agua} {
+		if valor < 0 || valor > 100 {
+			return errors.New("proteina, carboidrato, gordura e agua devem estar entre 0 e 100 g por 100 g")
+		}
+	}
+	return nil
+}
+
+// LerAlimentosCSV lê o catálogo de alimentos de um CSV separado por vírgula com cabeçalho nome,kcal,proteina,carboidrato,gordura,agua
+func LerAlimentosCSV(arquivo io.Reader) ([]Alimento, error) {
+	leitor := csv.NewReader(arquivo)
+	leitor.FieldsPerRecord = len(colunasAlimentosCSV)
+	cabecalho, erro := leitor.Read()
+	if erro != nil {
+		return nil, errors.New("cabecalho do csv de alimentos faltando ou mal formado")
+	}
+	for i, coluna := range colunasAlimentosCSV {
+		if strings.TrimSpace(cabecalho[i]) != coluna {
+			return nil, fmt.Errorf("coluna %d do csv de alimentos deveria ser %s", i+1, coluna)
+		}
+	}
+	var alimentos []Alimento
+	for linha := 2; ; linha++ {
+		registro, erro := leitor.Read()
+		if erro == io.EOF {
+			break
+		}
+		if erro != nil {
+			return nil, erro
+		}
+		alimento := Alimento{Nome: registro[0], EhSimples: 1}
+		valores := make([]float64, len(registro)-1)
+		for i, campo := range registro[1:] {
+			if valores[i], erro = strconv.ParseFloat(strings.TrimSpace(campo), 64); erro != nil {
+				return nil, fmt.Errorf("linha %d do csv de alimentos: %s invalido", linha, colunasAlimentosCSV[i+1])
+			}
+		}
+		alimento.Kcal = int(valores[0])
+		alimento.Proteina, alimento.Carboidrato, alimento.Gordura, alimento.Agua = valores[1], valores[2], valores[3], valores[4]
+		if erro = alimento.Validar(); erro != nil {
+			return nil, fmt.Errorf("linha %d do csv de alimentos: %w", linha, erro)
+		}
+		alimentos = append(alimentos, alimento)
+	}
+	return alimentos, nil
+}
+
+// AlimentoConsumido é um alimento de uma refeição, com os valores calculados para a quantidade consumida
+type AlimentoConsumido struct {
+	AlimentoID  int     `json:"alimento_id"`
+	ConsumidoID int     `json:"consumido_id,omitempty"`
+	EhSimples   *int    `json:"eh_simples"` // ponteiro para diferenciar o 0 (composto) do campo omitido
+	Quantidade  float64 `json:"quantidade"` // gramas
+	Kcal        int     `json:"kcal"`
+	Proteina    float64 `json:"proteina"`
+	Gordura     float64 `json:"gordura"`
+	Carboidrato float64 `json:"carboidrato"`
+	Agua        int     `json:"agua"` // ml de água contidos no alimento, somados à hidratação do dia
+	Nome        string  `json:"nome,omitempty"`
+}
+
+type ConsumoAlimentos struct {
+	UsuarioMatricula int                 `json:"usuario_matricula,omitempty"`
+	Data             time.Time           `json:"data"`
+	Consumidos       []AlimentoConsumido `json:"consumidos"`
+}
+
+// Validar verifica data e hora e os alimentos consumidos. Sem eh_simples o alimento é considerado simples
+func (c *ConsumoAlimentos) Validar() error {
+	if c.Data.IsZero() {
+		return errors.New("data e hora do consumo faltando")
+	}
+	if len(c.Consumidos) == 0 {
+		return errors.New("e necessario ter algum alimento consumido")
+	}
+	for i := range c.Consumidos {
+		consumido := &c.Consumidos[i]
+		if consumido.AlimentoID <= 0 {
+			return errors.New("id do alimento faltando")
+		}
+		if consumido.EhSimples == nil {
+			simples := 1
+			consumido.EhSimples = &simples
+		}
+		if *consumido.EhSimples != 1 {
+			return errors.New("alimentos compostos ainda nao sao suportados")
+		}
+		if consumido.Quantidade <= 0 || consumido.Quantidade > 10000 {
+			return errors.New("quantidade do alimento deve estar entre 0 e 10000 g")
+		}
+	}
+	return nil
+}
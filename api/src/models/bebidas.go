@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// BebidaAgua é a bebida usada quando um consumo não informa nenhuma
+const BebidaAgua = 1
+
+type Bebida struct {
+	ID               int     `json:"id,omitempty"`
+	Nome             string  `json:"nome,omitempty"`
+	Tipo             string  `json:"tipo,omitempty"`
+	FatorHidratacao  float64 `json:"fator_hidratacao"`
+	UsuarioMatricula int     `json:"usuario_matricula,omitempty"`
+}
+
+// Validar valida nome e fator de hidratação de uma bebida personalizada
+func (b *Bebida) Validar() error {
+	b.Nome = strings.TrimSpace(b.Nome)
+	if len(b.Nome) < 2 || len(b.Nome) > 30 {
+		return errors.New("nome da bebida deve ter entre 2 e 30 caracteres")
+	}
+	if b.FatorHidratacao < 0 || b.FatorHidratacao > 2 {
+		return errors.New("fator de hidratacao deve estar entre 0 e 2")
+	}
+	// Bebidas criadas por usuários são sempre personalizadas
+	b.Tipo = "personalizada"
+	return nil
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type Conquista struct {
+	ID            string     `json:"id"`
+	Nome          string     `json:"nome"`
+	Descricao     string     `json:"descricao"`
+	Metrica       string     `json:"metrica"`
+	Limite        float64    `json:"limite"`
+	Progresso     float64    `json:"progresso"` // valor atual da métrica do usuário
+	Conquistada   bool       `json:"conquistada"`
+	ConquistadaEm *time.Time `json:"conquistada_em,omitempty"`
+}
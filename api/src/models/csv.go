@@ -0,0 +1,155 @@
+package models
+
+import (
+	"API/src/utils"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ColunasCSV são os campos de um consumo lidos na importação, na ordem em que aparecem na exportação
+var ColunasCSV = []string{"id", "data", "quantidade", "bebida_id", "cafeina_mg", "unidades_alcool", "recipiente_id"}
+
+// mililitrosPorUnidade converte as unidades de volume aceitas na importação para ml
+var mililitrosPorUnidade = map[string]float64{"ml": 1, "l": 1000, "oz": 29.5735}
+
+type OpcoesCSV struct {
+	Colunas     map[string]string // campo do consumo para nome da coluna no arquivo. Campos omitidos usam o próprio nome
+	FormatoData string            // ex: dd/MM/yyyy HH:mm. Vazio usa yyyy-mm-ddThh:mm:ssZ
+	Unidade     string            // unidade da quantidade: ml, l ou oz
+	Separador   string
+	Local       *time.Location // fuso de datas sem fuso, normalmente o do usuário
+}
+
+// Validar verifica unidade, separador e colunas de uma importação de CSV, preenchendo os padrões ml e vírgula
+func (o *OpcoesCSV) Validar() error {
+	if o.Unidade == "" {
+		o.Unidade = "ml"
+	}
+	if _, existe := mililitrosPorUnidade[o.Unidade]; !existe {
+		return errors.New("unidade invalida, valores aceitos: ml, l e oz")
+	}
+	if o.Separador == "" {
+		o.Separador = ","
+	}
+	if utf8.RuneCountInString(o.Separador) != 1 || o.Separador == "\"" || o.Separador == "\n" {
+		return errors.New("separador deve ser um unico caractere")
+	}
+	if o.Colunas == nil {
+		o.Colunas = make(map[string]string)
+	}
+	for _, campo := range ColunasCSV {
+		if o.Colunas[campo] == "" {
+			o.Colunas[campo] = campo
+		}
+	}
+	return nil
+}
+
+// IndicesDasColunas localiza no cabeçalho do CSV a posição de cada campo. Campos ausentes ficam de fora, mas data e quantidade são obrigatórios
+func (o OpcoesCSV) IndicesDasColunas(cabecalho []string) (map[string]int, error) {
+	indices := make(map[string]int)
+	for _, campo := range ColunasCSV {
+		for i, coluna := range cabecalho {
+			if strings.EqualFold(strings.TrimSpace(coluna), o.Colunas[campo]) {
+				indices[campo] = i
+				break
+			}
+		}
+	}
+	if _, existe := indices["data"]; !existe {
+		return nil, fmt.Errorf("coluna %s nao encontrada no cabecalho", o.Colunas["data"])
+	}
+	_, temQuantidade := indices["quantidade"]
+	_, temRecipiente := indices["recipiente_id"]
+	if !temQuantidade && !temRecipiente {
+		return nil, fmt.Errorf("coluna %s nao encontrada no cabecalho", o.Colunas["quantidade"])
+	}
+	return indices, nil
+}
+
+// LerRegistroCSV converte uma linha do CSV em consumo, convertendo data para o fuso informado e quantidade para ml
+func (o OpcoesCSV) LerRegistroCSV(registro []string, indices map[string]int) (ConsumoAgua, error) {
+	valores := make(map[string]string)
+	for campo, i := range indices {
+		if i < len(registro) {
+			valores[campo] = strings.TrimSpace(registro[i])
+		}
+	}
+	var consumo ConsumoAgua
+	var erro error
+	consumo.ID = valores["id"]
+	if valores["data"] == "" {
+		return ConsumoAgua{}, errors.New("data e hora do consumo faltando")
+	}
+	if o.FormatoData == "" {
+		consumo.Data, erro = time.Parse(time.RFC3339, valores["data"])
+	} else {
+		consumo.Data, erro = time.ParseInLocation(utils.ConverterFormatoData(o.FormatoData), valores["data"], o.Local)
+	}
+	if erro != nil {
+		return ConsumoAgua{}, fmt.Errorf("data %s no formato errado", valores["data"])
+	}
+	quantidade, erro := lerNumeroCSV(valores["quantidade"])
+	if erro != nil {
+		return ConsumoAgua{}, errors.New("quantidade invalida")
+	}
+	consumo.Quantidade = int(math.Round(quantidade * mililitrosPorUnidade[o.Unidade]))
+	if consumo.CafeinaMg, erro = lerInteiroCSV(valores["cafeina_mg"]); erro != nil {
+		return ConsumoAgua{}, errors.New("cafeina_mg invalida")
+	}
+	if consumo.UnidadesAlcool, erro = lerNumeroCSV(valores["unidades_alcool"]); erro != nil {
+		return ConsumoAgua{}, errors.New("unidades_alcool invalidas")
+	}
+	if consumo.BebidaID, erro = lerInteiroCSV(valores["bebida_id"]); erro != nil {
+		return ConsumoAgua{}, errors.New("bebida_id invalido")
+	}
+	if consumo.RecipienteID, erro = lerInteiroCSV(valores["recipiente_id"]); erro != nil {
+		return ConsumoAgua{}, errors.New("recipiente_id invalido")
+	}
+	return consumo, nil
+}
+
+// lerNumeroCSV lê um número aceitando vírgula decimal. Célula vazia vale 0
+func lerNumeroCSV(valor string) (float64, error) {
+	if valor == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(strings.Replace(valor, ",", ".", 1), 64)
+}
+
+// lerInteiroCSV lê um número inteiro. Célula vazia vale 0
+func lerInteiroCSV(valor string) (int, error) {
+	if valor == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(valor)
+}
+
+// CabecalhoExportacaoCSV retorna o cabeçalho do CSV exportado, com hidratação e origem além dos campos importáveis
+func CabecalhoExportacaoCSV() []string {
+	return append(append([]string{}, ColunasCSV...), "hidratacao", "origem")
+}
+
+// RegistroCSV converte um consumo em linha do CSV exportado, com a data no fuso informado
+func (c ConsumoAgua) RegistroCSV(local *time.Location) []string {
+	recipienteID := ""
+	if c.RecipienteID != 0 {
+		recipienteID = strconv.Itoa(c.RecipienteID)
+	}
+	return []string{
+		c.ID,
+		c.Data.In(local).Format(time.RFC3339Nano),
+		strconv.Itoa(c.Quantidade),
+		strconv.Itoa(c.BebidaID),
+		strconv.Itoa(c.CafeinaMg),
+		strconv.FormatFloat(c.UnidadesAlcool, 'f', -1, 64),
+		recipienteID,
+		strconv.Itoa(c.Hidratacao),
+		c.Origem,
+	}
+}
@@ -0,0 +1,222 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestLerRegistroCSV(t *testing.T) {
+	saoPaulo, erro := time.LoadLocation("America/Sao_Paulo")
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	indices := map[string]int{"id": 0, "data": 1, "quantidade": 2, "bebida_id": 3, "cafeina_mg": 4, "unidades_alcool": 5, "recipiente_id": 6}
+	casos := []struct {
+		nome     string
+		opcoes   OpcoesCSV
+		indices  map[string]int
+		registro []string
+		esperado ConsumoAgua
+		erro     string
+	}{
+		{
+			nome:     "todas as colunas",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"8c7ba3ab-0f2e-4b4c-9d43-1d5e8bd2a8f1", "2024-05-10T08:30:00Z", "250", "2", "80", "0", "3"},
+			esperado: ConsumoAgua{ID: "8c7ba3ab-0f2e-4b4c-9d43-1d5e8bd2a8f1", Data: time.Date(2024, 5, 10, 8, 30, 0, 0, time.UTC), Quantidade: 250, BebidaID: 2, CafeinaMg: 80, RecipienteID: 3},
+		},
+		{
+			nome:     "celulas vazias valem zero e espacos sao ignorados",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", " 2024-05-10T08:30:00.123456Z ", " 300 ", "", "", "", ""},
+			esperado: ConsumoAgua{Data: time.Date(2024, 5, 10, 8, 30, 0, 123456000, time.UTC), Quantidade: 300},
+		},
+		{
+			nome:     "linha menor que o cabecalho",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", "2024-05-10T08:30:00Z", "300"},
+			esperado: ConsumoAgua{Data: time.Date(2024, 5, 10, 8, 30, 0, 0, time.UTC), Quantidade: 300},
+		},
+		{
+			nome:     "litros com virgula decimal e alcool",
+			opcoes:   OpcoesCSV{Unidade: "l"},
+			registro: []string{"", "2024-05-10T08:30:00Z", "0,33", "", "", "1,5", ""},
+			esperado: ConsumoAgua{Data: time.Date(2024, 5, 10, 8, 30, 0, 0, time.UTC), Quantidade: 330, UnidadesAlcool: 1.5},
+		},
+		{
+			nome:     "oncas arredondadas para ml",
+			opcoes:   OpcoesCSV{Unidade: "oz"},
+			registro: []string{"", "2024-05-10T08:30:00Z", "8", "", "", "", ""},
+			esperado: ConsumoAgua{Data: time.Date(2024, 5, 10, 8, 30, 0, 0, time.UTC), Quantidade: 237},
+		},
+		{
+			nome:     "formato de data sem fuso usa o fuso informado",
+			opcoes:   OpcoesCSV{Unidade: "ml", FormatoData: "dd/MM/yyyy HH:mm", Local: saoPaulo},
+			indices:  map[string]int{"data": 0, "quantidade": 1},
+			registro: []string{"10/05/2024 08:30", "200"},
+			esperado: ConsumoAgua{Data: time.Date(2024, 5, 10, 11, 30, 0, 0, time.UTC), Quantidade: 200},
+		},
+		{
+			nome:     "data faltando",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", "", "250", "", "", "", ""},
+			erro:     "data e hora do consumo faltando",
+		},
+		{
+			nome:     "data no formato errado",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", "10/05/2024", "250", "", "", "", ""},
+			erro:     "data 10/05/2024 no formato errado",
+		},
+		{
+			nome:     "quantidade invalida",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", "2024-05-10T08:30:00Z", "muito", "", "", "", ""},
+			erro:     "quantidade invalida",
+		},
+		{
+			nome:     "cafeina com casas decimais",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", "2024-05-10T08:30:00Z", "250", "", "80.5", "", ""},
+			erro:     "cafeina_mg invalida",
+		},
+		{
+			nome:     "unidades de alcool invalidas",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", "2024-05-10T08:30:00Z", "250", "", "", "x", ""},
+			erro:     "unidades_alcool invalidas",
+		},
+		{
+			nome:     "bebida invalida",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", "2024-05-10T08:30:00Z", "250", "cafe", "", "", ""},
+			erro:     "bebida_id invalido",
+		},
+		{
+			nome:     "recipiente invalido",
+			opcoes:   OpcoesCSV{Unidade: "ml"},
+			registro: []string{"", "2024-05-10T08:30:00Z", "", "", "", "", "copo"},
+			erro:     "recipiente_id invalido",
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			if caso.indices == nil {
+				caso.indices = indices
+			}
+			consumo, erro := caso.opcoes.LerRegistroCSV(caso.registro, caso.indices)
+			if caso.erro != "" {
+				if erro == nil || erro.Error() != caso.erro {
+					t.Fatalf("LerRegistroCSV() erro = %v, esperado %q", erro, caso.erro)
+				}
+				return
+			}
+			if erro != nil {
+				t.Fatalf("LerRegistroCSV() erro inesperado: %v", erro)
+			}
+			if !consumo.Data.Equal(caso.esperado.Data) {
+				t.Errorf("Data = %s, esperado %s", consumo.Data, caso.esperado.Data)
+			}
+			consumo.Data, caso.esperado.Data = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(consumo, caso.esperado) {
+				t.Errorf("LerRegistroCSV() = %+v, esperado %+v", consumo, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestIndicesDasColunas(t *testing.T) {
+	casos := []struct {
+		nome      string
+		colunas   map[string]string
+		cabecalho []string
+		esperado  map[string]int
+		erro      string
+	}{
+		{
+			nome:      "nomes padrao sem diferenciar maiusculas",
+			cabecalho: []string{"Data", " QUANTIDADE ", "outra"},
+			esperado:  map[string]int{"data": 0, "quantidade": 1},
+		},
+		{
+			nome:      "colunas renomeadas",
+			colunas:   map[string]string{"data": "quando", "quantidade": "ml"},
+			cabecalho: []string{"ml", "quando", "bebida_id"},
+			esperado:  map[string]int{"data": 1, "quantidade": 0, "bebida_id": 2},
+		},
+		{
+			nome:      "recipiente no lugar da quantidade",
+			cabecalho: []string{"data", "recipiente_id"},
+			esperado:  map[string]int{"data": 0, "recipiente_id": 1},
+		},
+		{
+			nome:      "sem data",
+			cabecalho: []string{"quantidade"},
+			erro:      "coluna data nao encontrada no cabecalho",
+		},
+		{
+			nome:      "sem quantidade nem recipiente",
+			colunas:   map[string]string{"quantidade": "ml"},
+			cabecalho: []string{"data", "quantidade"},
+			erro:      "coluna ml nao encontrada no cabecalho",
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			opcoes := OpcoesCSV{Colunas: caso.colunas}
+			if erro := opcoes.Validar(); erro != nil {
+				t.Fatal(erro)
+			}
+			indices, erro := opcoes.IndicesDasColunas(caso.cabecalho)
+			if caso.erro != "" {
+				if erro == nil || erro.Error() != caso.erro {
+					t.Fatalf("IndicesDasColunas() erro = %v, esperado %q", erro, caso.erro)
+				}
+				return
+			}
+			if erro != nil {
+				t.Fatalf("IndicesDasColunas() erro inesperado: %v", erro)
+			}
+			if !reflect.DeepEqual(indices, caso.esperado) {
+				t.Errorf("IndicesDasColunas() = %v, esperado %v", indices, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestRegistroCSVIdaEVolta(t *testing.T) {
+	saoPaulo, erro := time.LoadLocation("America/Sao_Paulo")
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	original := ConsumoAgua{
+		ID:             "8c7ba3ab-0f2e-4b4c-9d43-1d5e8bd2a8f1",
+		Data:           time.Date(2024, 5, 10, 8, 30, 15, 123456000, time.UTC),
+		Quantidade:     350,
+		BebidaID:       4,
+		CafeinaMg:      40,
+		UnidadesAlcool: 0.5,
+		RecipienteID:   7,
+	}
+	opcoes := OpcoesCSV{}
+	if erro := opcoes.Validar(); erro != nil {
+		t.Fatal(erro)
+	}
+	indices, erro := opcoes.IndicesDasColunas(CabecalhoExportacaoCSV())
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	lido, erro := opcoes.LerRegistroCSV(original.RegistroCSV(saoPaulo), indices)
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	if !lido.Data.Equal(original.Data) {
+		t.Errorf("Data = %s, esperado %s", lido.Data, original.Data)
+	}
+	lido.Data, original.Data = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(lido, original) {
+		t.Errorf("LerRegistroCSV(RegistroCSV()) = %+v, esperado %+v", lido, original)
+	}
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// janelasEstatisticas são os tamanhos de janela aceitos, em dias
+var janelasEstatisticas = map[string]int{"7d": 7, "30d": 30, "90d": 90}
+
+// DiasDaJanela retorna quantos dias tem uma janela de estatísticas (padrão 7d)
+func DiasDaJanela(janela string) (int, error) {
+	if janela == "" {
+		janela = "7d"
+	}
+	dias, existe := janelasEstatisticas[janela]
+	if !existe {
+		return 0, errors.New("janela invalida, valores aceitos: 7d, 30d e 90d")
+	}
+	return dias, nil
+}
+
+type TotalDia struct {
+	Dia        string `json:"dia"`
+	Total      int    `json:"total"`
+	Hidratacao int    `json:"hidratacao"`
+	AguaMeta   int    `json:"-"`
+}
+
+type EstatisticasAgua struct {
+	Janela                  string   `json:"janela"`
+	De                      string   `json:"de"`  // primeiro dia da janela (yyyy-mm-dd)
+	Ate                     string   `json:"ate"` // último dia da janela (yyyy-mm-dd)
+	Media                   float64  `json:"media"`
+	Mediana                 float64  `json:"mediana"`
+	DesvioPadrao            float64  `json:"desvio_padrao"`
+	MelhorDia               TotalDia `json:"melhor_dia"`
+	PiorDia                 TotalDia `json:"pior_dia"`
+	DiasComMeta             int      `json:"dias_com_meta"`
+	PorcentagemMetaAtingida float64  `json:"porcentagem_meta_atingida"`
+	Tendencia               float64  `json:"tendencia"` // variação média do total por dia (ml/dia), pela regressão linear
+}
+
+// CalcularEstatisticas calcula média, mediana, desvio padrão, melhor e pior dia, consistência na meta e tendência dos totais diários.
+// totais deve ter todos os dias da janela em ordem, com total 0 nos dias sem consumo. A meta é atingida pela hidratação efetiva
+func (e *EstatisticasAgua) CalcularEstatisticas(totais []TotalDia) {
+	n := len(totais)
+	if n == 0 {
+		return
+	}
+	e.De, e.Ate = totais[0].Dia, totais[n-1].Dia
+	e.MelhorDia, e.PiorDia = totais[0], totais[0]
+	soma, diasMetaAtingida := 0.0, 0
+	valores := make([]float64, n)
+	for i, total := range totais {
+		valores[i] = float64(total.Total)
+		soma += valores[i]
+		if total.Total > e.MelhorDia.Total {
+			e.MelhorDia = total
+		}
+		if total.Total < e.PiorDia.Total {
+			e.PiorDia = total
+		}
+		if total.AguaMeta > 0 {
+			e.DiasComMeta++
+			if total.Hidratacao >= total.AguaMeta {
+				diasMetaAtingida++
+			}
+		}
+	}
+	media := soma / float64(n)
+	// Desvio padrão populacional e tendência pelo método dos mínimos quadrados, com x sendo a posição do dia
+	somaQuadrados, covariancia, varianciaX := 0.0, 0.0, 0.0
+	mediaX := float64(n-1) / 2
+	for i, valor := range valores {
+		somaQuadrados += (valor - media) * (valor - media)
+		covariancia += (float64(i) - mediaX) * (valor - media)
+		varianciaX += (float64(i) - mediaX) * (float64(i) - mediaX)
+	}
+	e.Media = arredondar(media)
+	e.DesvioPadrao = arredondar(math.Sqrt(somaQuadrados / float64(n)))
+	if varianciaX > 0 {
+		e.Tendencia = arredondar(covariancia / varianciaX)
+	}
+	sort.Float64s(valores)
+	if n%2 == 1 {
+		e.Mediana = valores[n/2]
+	} else {
+		e.Mediana = (valores[n/2-1] + valores[n/2]) / 2
+	}
+	if e.DiasComMeta > 0 {
+		e.PorcentagemMetaAtingida = arredondar(float64(diasMetaAtingida) * 100 / float64(e.DiasComMeta))
+	}
+}
+
+// DiasDaSemana são os nomes das linhas do mapa de calor, começando na segunda-feira como nas semanas ISO
+var DiasDaSemana = [7]string{"segunda", "terca", "quarta", "quinta", "sexta", "sabado", "domingo"}
+
+type TotalHora struct {
+	DiaSemana int // 0 = segunda ... 6 = domingo
+	Hora      int
+	Total     int
+}
+
+type MapaDeCalorAgua struct {
+	De           time.Time      `json:"de"`
+	Ate          time.Time      `json:"ate"`
+	DiasDaSemana [7]string      `json:"dias_da_semana"`
+	Media        [7][24]float64 `json:"media"` // média em ml de cada hora de cada dia da semana, no horário local do usuário
+}
+
+// CalcularMedias divide o total de cada hora de cada dia da semana pela quantidade de vezes que essa hora aparece no período,
+// assim horas sem consumo contam como 0 e não somem da média
+func (m *MapaDeCalorAgua) CalcularMedias(totais []TotalHora, local *time.Location) {
+	m.DiasDaSemana = DiasDaSemana
+	var ocorrencias [7][24]int
+	inicio := m.De.In(local)
+	for instante := time.Date(inicio.Year(), inicio.Month(), inicio.Day(), inicio.Hour(), 0, 0, 0, local); instante.Before(m.Ate); instante = instante.Add(time.Hour) {
+		hora := instante.In(local)
+		ocorrencias[(int(hora.Weekday())+6)%7][hora.Hour()]++
+	}
+	for _, total := range totais {
+		if ocorrencias[total.DiaSemana][total.Hora] > 0 {
+			m.Media[total.DiaSemana][total.Hora] = arredondar(float64(total.Total) / float64(ocorrencias[total.DiaSemana][total.Hora]))
+		}
+	}
+}
+
+// arredondar arredonda para duas casas decimais
+func arredondar(valor float64) float64 {
+	return math.Round(valor*100) / 100
+}
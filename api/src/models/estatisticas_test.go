@@ -0,0 +1,106 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiasDaJanela(t *testing.T) {
+	casos := []struct {
+		janela   string
+		esperado int
+		erro     bool
+	}{
+		{"", 7, false},
+		{"7d", 7, false},
+		{"30d", 30, false},
+		{"90d", 90, false},
+		{"15d", 0, true},
+		{"7", 0, true},
+	}
+	for _, caso := range casos {
+		dias, erro := DiasDaJanela(caso.janela)
+		if (erro != nil) != caso.erro || dias != caso.esperado {
+			t.Errorf("DiasDaJanela(%q) = %d, %v, esperado %d com erro %v", caso.janela, dias, erro, caso.esperado, caso.erro)
+		}
+	}
+}
+
+func TestCalcularEstatisticas(t *testing.T) {
+	casos := []struct {
+		nome     string
+		totais   []TotalDia
+		esperado EstatisticasAgua
+	}{
+		{
+			nome:     "sem dias",
+			totais:   nil,
+			esperado: EstatisticasAgua{Janela: "7d"},
+		},
+		{
+			nome:   "um dia",
+			totais: []TotalDia{{Dia: "2024-05-01", Total: 1800, Hidratacao: 1700, AguaMeta: 2000}},
+			esperado: EstatisticasAgua{
+				Janela: "7d", De: "2024-05-01", Ate: "2024-05-01",
+				Media: 1800, Mediana: 1800,
+				MelhorDia:   TotalDia{Dia: "2024-05-01", Total: 1800, Hidratacao: 1700, AguaMeta: 2000},
+				PiorDia:     TotalDia{Dia: "2024-05-01", Total: 1800, Hidratacao: 1700, AguaMeta: 2000},
+				DiasComMeta: 1,
+			},
+		},
+		{
+			nome: "quantidade par de dias com dia sem consumo",
+			totais: []TotalDia{
+				{Dia: "2024-05-01", Total: 1000, Hidratacao: 1000, AguaMeta: 2000},
+				{Dia: "2024-05-02", Total: 2000, Hidratacao: 2000, AguaMeta: 2000},
+				{Dia: "2024-05-03", Total: 3000, Hidratacao: 3000, AguaMeta: 2000},
+				{Dia: "2024-05-04", Total: 0, Hidratacao: 0, AguaMeta: 2000},
+			},
+			esperado: EstatisticasAgua{
+				Janela: "7d", De: "2024-05-01", Ate: "2024-05-04",
+				Media: 1500, Mediana: 1500, DesvioPadrao: 1118.03,
+				MelhorDia:   TotalDia{Dia: "2024-05-03", Total: 3000, Hidratacao: 3000, AguaMeta: 2000},
+				PiorDia:     TotalDia{Dia: "2024-05-04", Total: 0, Hidratacao: 0, AguaMeta: 2000},
+				DiasComMeta: 4, PorcentagemMetaAtingida: 50, Tendencia: -200,
+			},
+		},
+		{
+			nome: "dias sem meta ficam fora da porcentagem",
+			totais: []TotalDia{
+				{Dia: "2024-05-01", Total: 500, Hidratacao: 500, AguaMeta: 0},
+				{Dia: "2024-05-02", Total: 1500, Hidratacao: 1500, AguaMeta: 1000},
+				{Dia: "2024-05-03", Total: 1000, Hidratacao: 1000, AguaMeta: 1000},
+			},
+			esperado: EstatisticasAgua{
+				Janela: "7d", De: "2024-05-01", Ate: "2024-05-03",
+				Media: 1000, Mediana: 1000, DesvioPadrao: 408.25,
+				MelhorDia:   TotalDia{Dia: "2024-05-02", Total: 1500, Hidratacao: 1500, AguaMeta: 1000},
+				PiorDia:     TotalDia{Dia: "2024-05-01", Total: 500, Hidratacao: 500, AguaMeta: 0},
+				DiasComMeta: 2, PorcentagemMetaAtingida: 100, Tendencia: 250,
+			},
+		},
+		{
+			nome: "meta pela hidratacao e empate fica com o primeiro dia",
+			totais: []TotalDia{
+				{Dia: "2024-05-01", Total: 2000, Hidratacao: 1900, AguaMeta: 2000},
+				{Dia: "2024-05-02", Total: 2000, Hidratacao: 2100, AguaMeta: 2000},
+			},
+			esperado: EstatisticasAgua{
+				Janela: "7d", De: "2024-05-01", Ate: "2024-05-02",
+				Media: 2000, Mediana: 2000,
+				MelhorDia:   TotalDia{Dia: "2024-05-01", Total: 2000, Hidratacao: 1900, AguaMeta: 2000},
+				PiorDia:     TotalDia{Dia: "2024-05-01", Total: 2000, Hidratacao: 1900, AguaMeta: 2000},
+				DiasComMeta: 2, PorcentagemMetaAtingida: 50,
+			},
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			estatisticas := EstatisticasAgua{Janela: "7d"}
+			estatisticas.CalcularEstatisticas(caso.totais)
+			if !reflect.DeepEqual(estatisticas, caso.esperado) {
+				t.Errorf("CalcularEstatisticas() = %+v, esperado %+v", estatisticas, caso.esperado)
+			}
+		})
+	}
+}
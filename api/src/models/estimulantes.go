@@ -0,0 +1,40 @@
+package models
+
+import "errors"
+
+type Limites struct {
+	LimiteCafeinaMg      *int     `json:"limite_cafeina_mg"`
+	LimiteUnidadesAlcool *float64 `json:"limite_unidades_alcool"`
+}
+
+type EstimulantesDia struct {
+	Dia            string  `json:"dia"`
+	CafeinaMg      int     `json:"cafeina_mg"`
+	UnidadesAlcool float64 `json:"unidades_alcool"`
+	Limites
+	CafeinaExcedida bool `json:"cafeina_excedida"`
+	AlcoolExcedido  bool `json:"alcool_excedido"`
+}
+
+// MaximoUnidadesAlcool é o maior valor aceito para unidades de álcool, de um consumo ou do limite diário
+const MaximoUnidadesAlcool = 100
+
+// ValidarLimites verifica se os limites diários não são negativos nem grandes demais. Limite nulo significa sem limite
+func (l Limites) ValidarLimites() error {
+	if l.LimiteCafeinaMg != nil && *l.LimiteCafeinaMg < 0 {
+		return errors.New("limite de cafeina nao pode ser negativo")
+	}
+	if l.LimiteUnidadesAlcool != nil && *l.LimiteUnidadesAlcool < 0 {
+		return errors.New("limite de unidades de alcool nao pode ser negativo")
+	}
+	if l.LimiteUnidadesAlcool != nil && *l.LimiteUnidadesAlcool > MaximoUnidadesAlcool {
+		return errors.New("limite de unidades de alcool deve ser no maximo 100")
+	}
+	return nil
+}
+
+// VerificarLimites marca quais limites diários foram ultrapassados
+func (e *EstimulantesDia) VerificarLimites() {
+	e.CafeinaExcedida = e.LimiteCafeinaMg != nil && e.CafeinaMg > *e.LimiteCafeinaMg
+	e.AlcoolExcedido = e.LimiteUnidadesAlcool != nil && e.UnidadesAlcool > *e.LimiteUnidadesAlcool
+}
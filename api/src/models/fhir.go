@@ -0,0 +1,144 @@
+package models
+
+import (
+	"API/src/utils"
+	"strconv"
+	"time"
+)
+
+// Código LOINC usado nas Observations de consumo de água (ingestão oral de líquidos)
+const (
+	CodigoLOINCIngestaoOral  = "9000-1"
+	DisplayLOINCIngestaoOral = "Fluid intake oral"
+)
+
+type CodingFHIR struct {
+	System  string `json:"system"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+type ConceitoFHIR struct {
+	Coding []CodingFHIR `json:"coding"`
+	Text   string       `json:"text,omitempty"`
+}
+
+type ReferenciaFHIR struct {
+	Reference string `json:"reference"`
+}
+
+type QuantidadeFHIR struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit"`
+	System string  `json:"system"`
+	Code   string  `json:"code"`
+}
+
+type IdentificadorFHIR struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+type NomeFHIR struct {
+	Family string   `json:"family"`
+	Given  []string `json:"given"`
+}
+
+type ContatoFHIR struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+// PacienteFHIR é um recurso Patient do FHIR R4
+type PacienteFHIR struct {
+	ResourceType string              `json:"resourceType"`
+	ID           string              `json:"id"`
+	Identifier   []IdentificadorFHIR `json:"identifier"`
+	Name         []NomeFHIR          `json:"name"`
+	Telecom      []ContatoFHIR       `json:"telecom,omitempty"`
+	Gender       string              `json:"gender"`
+	BirthDate    string              `json:"birthDate,omitempty"`
+}
+
+// ObservacaoFHIR é um recurso Observation do FHIR R4
+type ObservacaoFHIR struct {
+	ResourceType      string         `json:"resourceType"`
+	ID                string         `json:"id"`
+	Status            string         `json:"status"`
+	Code              ConceitoFHIR   `json:"code"`
+	Subject           ReferenciaFHIR `json:"subject"`
+	EffectiveDateTime string         `json:"effectiveDateTime"`
+	ValueQuantity     QuantidadeFHIR `json:"valueQuantity"`
+}
+
+type EntradaFHIR struct {
+	FullURL  string      `json:"fullUrl"`
+	Resource interface{} `json:"resource"`
+}
+
+// BundleFHIR é um recurso Bundle do FHIR R4 do tipo collection
+type BundleFHIR struct {
+	ResourceType string        `json:"resourceType"`
+	Type         string        `json:"type"`
+	Timestamp    string        `json:"timestamp"`
+	Entry        []EntradaFHIR `json:"entry"`
+}
+
+// NovoPacienteFHIR converte um usuário em Patient, com id estável derivado da matrícula
+func NovoPacienteFHIR(usuario Usuario) PacienteFHIR {
+	paciente := PacienteFHIR{
+		ResourceType: "Patient",
+		ID:           utils.GerarUUIDDeNome("paciente|" + strconv.Itoa(usuario.Matricula)),
+		Identifier:   []IdentificadorFHIR{{System: "urn:pro-health:matricula", Value: strconv.Itoa(usuario.Matricula)}},
+		Name:         []NomeFHIR{{Family: usuario.Sobrenome, Given: []string{usuario.Nome}}},
+		Gender:       "unknown",
+	}
+	if usuario.Celular != "" {
+		paciente.Telecom = append(paciente.Telecom, ContatoFHIR{System: "phone", Value: usuario.Celular})
+	}
+	if usuario.Email != "" {
+		paciente.Telecom = append(paciente.Telecom, ContatoFHIR{System: "email", Value: usuario.Email})
+	}
+	switch usuario.Sexo {
+	case "M", "m":
+		paciente.Gender = "male"
+	case "F", "f":
+		paciente.Gender = "female"
+	}
+	// Data de nascimento pode vir do banco com horário (yyyy-mm-ddT00:00:00Z)
+	if len(usuario.DataNascimento) >= 10 {
+		paciente.BirthDate = usuario.DataNascimento[:10]
+	}
+	return paciente
+}
+
+// NovaObservacaoFHIR converte um consumo de água em Observation do paciente, com a quantidade em ml
+func NovaObservacaoFHIR(consumo ConsumoAgua, paciente PacienteFHIR) ObservacaoFHIR {
+	return ObservacaoFHIR{
+		ResourceType: "Observation",
+		ID:           consumo.ID,
+		Status:       "final",
+		Code: ConceitoFHIR{
+			Coding: []CodingFHIR{{System: "http://loinc.org", Code: CodigoLOINCIngestaoOral, Display: DisplayLOINCIngestaoOral}},
+			Text:   "Consumo de água",
+		},
+		Subject:           ReferenciaFHIR{Reference: "urn:uuid:" + paciente.ID},
+		EffectiveDateTime: consumo.Data.UTC().Format(time.RFC3339),
+		ValueQuantity:     QuantidadeFHIR{Value: float64(consumo.Quantidade), Unit: "mL", System: "http://unitsofmeasure.org", Code: "mL"},
+	}
+}
+
+// NovoBundleFHIR monta um Bundle com o paciente seguido de uma Observation por consumo de água
+func NovoBundleFHIR(usuario Usuario, consumos []ConsumoAgua) BundleFHIR {
+	paciente := NovoPacienteFHIR(usuario)
+	bundle := BundleFHIR{
+		ResourceType: "Bundle",
+		Type:         "collection",
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+		Entry:        []EntradaFHIR{{FullURL: "urn:uuid:" + paciente.ID, Resource: paciente}},
+	}
+	for _, consumo := range consumos {
+		bundle.Entry = append(bundle.Entry, EntradaFHIR{FullURL: "urn:uuid:" + consumo.ID, Resource: NovaObservacaoFHIR(consumo, paciente)})
+	}
+	return bundle
+}
@@ -0,0 +1,19 @@
+package models
+
+import "errors"
+
+type RequisicaoIdempotente struct {
+	UsuarioMatricula int
+	Chave            string // valor do cabeçalho Idempotency-Key, ou HMAC dele com a requisição sem usuário logado
+	HashRequisicao   string // HMAC-SHA256 de método, rota, query e corpo da primeira requisição
+	StatusCode       int    // 0 enquanto a primeira requisição ainda está sendo processada
+	Corpo            []byte
+}
+
+// Validar verifica o tamanho da chave de idempotência
+func (r RequisicaoIdempotente) Validar() error {
+	if len(r.Chave) > 255 {
+		return errors.New("Idempotency-Key deve ter no maximo 255 caracteres")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package models
+
+import "errors"
+
+// MaximoLote é a quantidade máxima de consumos aceita em uma importação
+const MaximoLote = 10000
+
+// Modos de importação: transacao insere tudo ou nada, parcial insere as linhas válidas
+const (
+	ModoTransacao = "transacao"
+	ModoParcial   = "parcial"
+)
+
+// Tratamentos para consumos importados com id já existente
+const (
+	ConflitoFalhar       = "falhar"
+	ConflitoPular        = "pular"
+	ConflitoSobrescrever = "sobrescrever"
+)
+
+// Situações de uma linha após a importação
+const (
+	LinhaCriada      = "criado"
+	LinhaSobrescrita = "sobrescrito"
+	LinhaIgnorada    = "ignorado"
+	LinhaComErro     = "erro"
+	LinhaRevertida   = "revertido" // válida, mas desfeita porque outra linha falhou no modo transacao
+)
+
+type OpcoesLote struct {
+	Modo              string
+	Conflito          string
+	IgnorarDuplicados bool // ignora consumos com mesma data e quantidade de um já registrado
+}
+
+// Validar verifica modo e tratamento de conflito de uma importação, preenchendo os padrões transacao e falhar
+func (o *OpcoesLote) Validar() error {
+	if o.Modo == "" {
+		o.Modo = ModoTransacao
+	}
+	if o.Modo != ModoTransacao && o.Modo != ModoParcial {
+		return errors.New("modo invalido, valores aceitos: transacao e parcial")
+	}
+	if o.Conflito == "" {
+		o.Conflito = ConflitoFalhar
+	}
+	if o.Conflito != ConflitoFalhar && o.Conflito != ConflitoPular && o.Conflito != ConflitoSobrescrever {
+		return errors.New("conflito invalido, valores aceitos: falhar, pular e sobrescrever")
+	}
+	return nil
+}
+
+type ResultadoLote struct {
+	Linha  int    `json:"linha"` // posição do consumo no lote, começando em 1
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Erro   string `json:"erro,omitempty"`
+}
+
+type RelatorioLote struct {
+	Total        int             `json:"total"`
+	Criados      int             `json:"criados"`
+	Sobrescritos int             `json:"sobrescritos"`
+	Ignorados    int             `json:"ignorados"`
+	Erros        int             `json:"erros"`
+	Revertido    bool            `json:"revertido"`
+	Resultados   []ResultadoLote `json:"resultados"`
+}
+
+// NovoRelatorioLote monta o relatório de uma importação contando as linhas de cada situação
+func NovoRelatorioLote(resultados []ResultadoLote) RelatorioLote {
+	relatorio := RelatorioLote{Total: len(resultados), Resultados: resultados}
+	for _, resultado := range resultados {
+		switch resultado.Status {
+		case LinhaCriada:
+			relatorio.Criados++
+		case LinhaSobrescrita:
+			relatorio.Sobrescritos++
+		case LinhaIgnorada:
+			relatorio.Ignorados++
+		case LinhaComErro:
+			relatorio.Erros++
+		case LinhaRevertida:
+			relatorio.Revertido = true
+		}
+	}
+	return relatorio
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestCalcularMedias(t *testing.T) {
+	saoPaulo, erro := time.LoadLocation("America/Sao_Paulo")
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	novaYork, erro := time.LoadLocation("America/New_York")
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	type media struct {
+		diaSemana, hora int
+		valor           float64
+	}
+	casos := []struct {
+		nome     string
+		local    *time.Location
+		de, ate  time.Time
+		totais   []TotalHora
+		esperado []media
+	}{
+		{
+			nome:  "sem consumos",
+			local: saoPaulo,
+			de:    time.Date(2024, 5, 6, 0, 0, 0, 0, saoPaulo),
+			ate:   time.Date(2024, 5, 13, 0, 0, 0, 0, saoPaulo),
+		},
+		{
+			nome:  "duas semanas dividem o total por duas ocorrencias",
+			local: saoPaulo,
+			de:    time.Date(2024, 5, 6, 0, 0, 0, 0, saoPaulo),
+			ate:   time.Date(2024, 5, 20, 0, 0, 0, 0, saoPaulo),
+			totais: []TotalHora{
+				{DiaSemana: 0, Hora: 8, Total: 3000},
+				{DiaSemana: 6, Hora: 23, Total: 500},
+			},
+			esperado: []media{{0, 8, 1500}, {6, 23, 250}},
+		},
+		{
+			nome:  "hora que nao aparece no periodo fica zerada",
+			local: saoPaulo,
+			de:    time.Date(2024, 5, 6, 0, 0, 0, 0, saoPaulo),
+			ate:   time.Date(2024, 5, 7, 0, 0, 0, 0, saoPaulo),
+			totais: []TotalHora{
+				{DiaSemana: 0, Hora: 10, Total: 700},
+				{DiaSemana: 1, Hora: 10, Total: 300},
+			},
+			esperado: []media{{0, 10, 700}},
+		},
+		{
+			// 2h não existe no domingo de início do horário de verão
+			nome:  "inicio do horario de verao",
+			local: novaYork,
+			de:    time.Date(2024, 3, 10, 0, 0, 0, 0, novaYork),
+			ate:   time.Date(2024, 3, 11, 0, 0, 0, 0, novaYork),
+			totais: []TotalHora{
+				{DiaSemana: 6, Hora: 2, Total: 400},
+				{DiaSemana: 6, Hora: 3, Total: 700},
+			},
+			esperado: []media{{6, 3, 700}},
+		},
+		{
+			// 1h acontece duas vezes no domingo de fim do horário de verão
+			nome:  "fim do horario de verao",
+			local: novaYork,
+			de:    time.Date(2024, 11, 3, 0, 0, 0, 0, novaYork),
+			ate:   time.Date(2024, 11, 4, 0, 0, 0, 0, novaYork),
+			totais: []TotalHora{
+				{DiaSemana: 6, Hora: 1, Total: 600},
+				{DiaSemana: 6, Hora: 2, Total: 600},
+			},
+			esperado: []media{{6, 1, 300}, {6, 2, 600}},
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			mapa := MapaDeCalorAgua{De: caso.de, Ate: caso.ate}
+			mapa.CalcularMedias(caso.totais, caso.local)
+			if mapa.DiasDaSemana != DiasDaSemana {
+				t.Errorf("DiasDaSemana = %v, esperado %v", mapa.DiasDaSemana, DiasDaSemana)
+			}
+			var esperado [7][24]float64
+			for _, m := range caso.esperado {
+				esperado[m.diaSemana][m.hora] = m.valor
+			}
+			if mapa.Media != esperado {
+				t.Errorf("Media = %v, esperado %v", mapa.Media, esperado)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type Medida struct {
+	Dono        int       `json:"dono,omitempty"`
+	Data        time.Time `json:"data"`
+	Peso        float64   `json:"peso"` // kg
+	Ombro       float64   `json:"ombro"`
+	Peito       float64   `json:"peito"`
+	Braco       float64   `json:"braco"`
+	Antebraco   float64   `json:"antebraco"`
+	Cintura     float64   `json:"cintura"`
+	Quadril     float64   `json:"quadril"`
+	Coxa        float64   `json:"coxa"`
+	Panturrilha float64   `json:"panturrilha"`
+}
+
+// Validar verifica se data e hora da medida estão presentes e valida as medidas
+func (m *Medida) Validar() error {
+	if m.Data.IsZero() {
+		return errors.New("data e hora da medida faltando")
+	}
+	return m.ValidarMedidas()
+}
+
+// ValidarMedidas verifica se peso (20 a 500 kg) e circunferências (1 a 300 cm) estão presentes e são plausíveis
+func (m *Medida) ValidarMedidas() error {
+	if m.Peso < 20 || m.Peso > 500 {
+		return errors.New("peso deve estar entre 20 e 500 kg")
+	}
+	// Slice em vez de map para que o erro informado seja sempre o da primeira circunferência inválida, na mesma ordem
+	circunferencias := []struct {
+		nome  string
+		valor float64
+	}{{"ombro", m.Ombro}, {"peito", m.Peito}, {"braco", m.Braco}, {"antebraco", m.Antebraco},
+		{"cintura", m.Cintura}, {"quadril", m.Quadril}, {"coxa", m.Coxa}, {"panturrilha", m.Panturrilha}}
+	for _, circunferencia := range circunferencias {
+		if circunferencia.valor < 1 || circunferencia.valor > 300 {
+			return errors.New(circunferencia.nome + " deve estar entre 1 e 300 cm")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type Metas struct {
+	AguaMeta *int `json:"agua_meta,omitempty"`
+}
+
+type MetaAgua struct {
+	ValidaDesde string `json:"valida_desde,omitempty"` //yyyy-mm-dd
+	AguaMeta    int    `json:"agua_meta"`
+}
+
+// ValidarMetas verifica se todas as metas estão presentes e se nenhuma é negativa
+func (m Metas) ValidarMetas() error {
+	if m.AguaMeta == nil {
+		return errors.New("alguma meta esta faltando. se deseja nao ter uma a envie com valor 0")
+	}
+	if *m.AguaMeta < 0 {
+		return errors.New("a meta de agua nao pode ser negativa")
+	}
+	return nil
+}
+
+// Validar verifica o formato da data de início da meta e se a meta não é negativa
+func (m MetaAgua) Validar() error {
+	if _, erro := time.Parse("2006-01-02", m.ValidaDesde); erro != nil {
+		return errors.New("data de inicio da meta invalida, formato esperado: yyyy-mm-dd")
+	}
+	if m.AguaMeta < 0 {
+		return errors.New("a meta de agua nao pode ser negativa")
+	}
+	return nil
+}
+
+// MetaAguaDoDia retorna a meta que estava em vigor em um dia (yyyy-mm-dd) dado o histórico de metas ordenado por data de início,
+// ou a meta padrão se nenhuma estava
+func MetaAguaDoDia(historico []MetaAgua, dia string, metaPadrao int) int {
+	aguaMeta := metaPadrao
+	for _, meta := range historico {
+		// Datas no formato yyyy-mm-dd podem ser comparadas como texto
+		if meta.ValidaDesde > dia {
+			break
+		}
+		aguaMeta = meta.AguaMeta
+	}
+	return aguaMeta
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+type Recipiente struct {
+	ID               int    `json:"id,omitempty"`
+	UsuarioMatricula int    `json:"usuario_matricula,omitempty"`
+	Nome             string `json:"nome,omitempty"`
+	Capacidade       int    `json:"capacidade,omitempty"` // em ml
+}
+
+// Validar valida nome e capacidade de um recipiente
+func (r *Recipiente) Validar() error {
+	r.Nome = strings.TrimSpace(r.Nome)
+	if len(r.Nome) < 2 || len(r.Nome) > 30 {
+		return errors.New("nome do recipiente deve ter entre 2 e 30 caracteres")
+	}
+	if r.Capacidade <= 0 {
+		return errors.New("a capacidade do recipiente deve ser maior que 0")
+	}
+	return nil
+}
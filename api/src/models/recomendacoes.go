@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// acrescimosAtividade são os ml somados à recomendação diária pela frequência de atividade física
+var acrescimosAtividade = map[string]int{AtividadeSedentaria: 0, AtividadeLeve: 250, AtividadeModerada: 500, AtividadeIntensa: 750}
+
+const (
+	// TemperaturaDeReferencia é a temperatura ambiente (°C) a partir da qual a recomendação aumenta
+	TemperaturaDeReferencia = 25.0
+	// MlPorGrauAcima é o acréscimo em ml por grau acima da temperatura de referência
+	MlPorGrauAcima = 50
+)
+
+type EntradasRecomendacao struct {
+	Idade           int      `json:"idade"`
+	Sexo            string   `json:"sexo"`
+	Peso            float64  `json:"peso"`
+	AtividadeFisica string   `json:"atividade_fisica"`
+	Temperatura     *float64 `json:"temperatura,omitempty"`
+}
+
+type RecomendacaoAgua struct {
+	Recomendado          int                  `json:"recomendado"` // ml por dia, arredondado para múltiplo de 50
+	Entradas             EntradasRecomendacao `json:"entradas"`
+	MlPorKg              float64              `json:"ml_por_kg"`
+	Base                 float64              `json:"base"` // peso x ml_por_kg
+	FatorSexo            float64              `json:"fator_sexo"`
+	AcrescimoAtividade   int                  `json:"acrescimo_atividade"`
+	AcrescimoTemperatura int                  `json:"acrescimo_temperatura"`
+	Formula              string               `json:"formula"`
+}
+
+// ValidarTemperatura verifica se a temperatura ambiente informada (°C) é plausível
+func ValidarTemperatura(temperatura float64) error {
+	if temperatura < -50 || temperatura > 60 {
+		return errors.New("temperatura deve estar entre -50 e 60 graus")
+	}
+	return nil
+}
+
+// mlPorKgDaIdade retorna a quantidade diária de água por kg de peso recomendada para a idade
+func mlPorKgDaIdade(idade int) float64 {
+	switch {
+	case idade <= 30:
+		return 40
+	case idade <= 55:
+		return 35
+	case idade <= 65:
+		return 30
+	default:
+		return 25
+	}
+}
+
+// NovaRecomendacaoAgua calcula a recomendação diária de água do usuário a partir da idade em hoje, sexo, peso, atividade física
+// e da temperatura ambiente, se informada. Todas as entradas e parcelas da fórmula são devolvidas para que o cálculo possa ser conferido
+func NovaRecomendacaoAgua(usuario Usuario, hoje time.Time, temperatura *float64) (RecomendacaoAgua, error) {
+	if usuario.Peso == 0 || usuario.AtividadeFisica == "" {
+		return RecomendacaoAgua{}, errors.New("peso e atividade fisica nao cadastrados, atualize os dados adicionais")
+	}
+	if len(usuario.DataNascimento) < 10 {
+		return RecomendacaoAgua{}, errors.New("data de nascimento invalida")
+	}
+	nascimento, erro := time.Parse("2006-01-02", usuario.DataNascimento[:10])
+	if erro != nil {
+		return RecomendacaoAgua{}, errors.New("data de nascimento invalida")
+	}
+	idade := hoje.Year() - nascimento.Year()
+	if hoje.Month() < nascimento.Month() || (hoje.Month() == nascimento.Month() && hoje.Day() < nascimento.Day()) {
+		idade--
+	}
+	r := RecomendacaoAgua{
+		Entradas:           EntradasRecomendacao{Idade: idade, Sexo: usuario.Sexo, Peso: usuario.Peso, AtividadeFisica: usuario.AtividadeFisica, Temperatura: temperatura},
+		MlPorKg:            mlPorKgDaIdade(idade),
+		FatorSexo:          1,
+		AcrescimoAtividade: acrescimosAtividade[usuario.AtividadeFisica],
+		Formula:            "arredondar_50(peso * ml_por_kg * fator_sexo + acrescimo_atividade + acrescimo_temperatura)",
+	}
+	// Mulheres têm em média menos massa magra, e com ela menos água corporal, por kg de peso
+	if usuario.Sexo == "F" {
+		r.FatorSexo = 0.9
+	}
+	r.Base = arredondar(usuario.Peso * r.MlPorKg)
+	if temperatura != nil && *temperatura > TemperaturaDeReferencia {
+		r.AcrescimoTemperatura = int(math.Round((*temperatura - TemperaturaDeReferencia) * MlPorGrauAcima))
+	}
+	total := r.Base*r.FatorSexo + float64(r.AcrescimoAtividade+r.AcrescimoTemperatura)
+	r.Recomendado = int(math.Round(total/50) * 50)
+	return r, nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNovaRecomendacaoAgua(t *testing.T) {
+	temperatura := func(valor float64) *float64 { return &valor }
+	hoje := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	casos := []struct {
+		nome                 string
+		usuario              Usuario
+		temperatura          *float64
+		idade                int
+		mlPorKg              float64
+		acrescimoTemperatura int
+		recomendado          int
+		erro                 string
+	}{
+		{
+			nome:    "sedentario sem temperatura",
+			usuario: Usuario{Sexo: "M", DataNascimento: "1999-01-01", DadosAdicionais: DadosAdicionais{Peso: 70, AtividadeFisica: AtividadeSedentaria}},
+			idade:   25, mlPorKg: 40, recomendado: 2800,
+		},
+		{
+			nome:        "mulher com atividade moderada e calor",
+			usuario:     Usuario{Sexo: "F", DataNascimento: "1984-01-01", DadosAdicionais: DadosAdicionais{Peso: 60, AtividadeFisica: AtividadeModerada}},
+			temperatura: temperatura(30),
+			idade:       40, mlPorKg: 35, acrescimoTemperatura: 250, recomendado: 2650,
+		},
+		{
+			nome:        "temperatura abaixo da referencia nao soma",
+			usuario:     Usuario{Sexo: "M", DataNascimento: "1954-01-01", DadosAdicionais: DadosAdicionais{Peso: 50, AtividadeFisica: AtividadeIntensa}},
+			temperatura: temperatura(20),
+			idade:       70, mlPorKg: 25, recomendado: 2000,
+		},
+		{
+			nome:    "vespera do aniversario de 31 anos",
+			usuario: Usuario{Sexo: "M", DataNascimento: "1993-05-11", DadosAdicionais: DadosAdicionais{Peso: 80, AtividadeFisica: AtividadeLeve}},
+			idade:   30, mlPorKg: 40, recomendado: 3450,
+		},
+		{
+			nome:    "dia do aniversario de 31 anos",
+			usuario: Usuario{Sexo: "M", DataNascimento: "1993-05-10", DadosAdicionais: DadosAdicionais{Peso: 80, AtividadeFisica: AtividadeLeve}},
+			idade:   31, mlPorKg: 35, recomendado: 3050,
+		},
+		{
+			nome:    "data de nascimento com horario",
+			usuario: Usuario{Sexo: "M", DataNascimento: "1960-01-01T00:00:00Z", DadosAdicionais: DadosAdicionais{Peso: 63.3, AtividadeFisica: AtividadeSedentaria}},
+			idade:   64, mlPorKg: 30, recomendado: 1900,
+		},
+		{
+			nome:    "sem peso",
+			usuario: Usuario{DataNascimento: "1990-01-01", DadosAdicionais: DadosAdicionais{AtividadeFisica: AtividadeLeve}},
+			erro:    "peso e atividade fisica nao cadastrados, atualize os dados adicionais",
+		},
+		{
+			nome:    "sem atividade fisica",
+			usuario: Usuario{DataNascimento: "1990-01-01", DadosAdicionais: DadosAdicionais{Peso: 70}},
+			erro:    "peso e atividade fisica nao cadastrados, atualize os dados adicionais",
+		},
+		{
+			nome:    "sem data de nascimento",
+			usuario: Usuario{DadosAdicionais: DadosAdicionais{Peso: 70, AtividadeFisica: AtividadeLeve}},
+			erro:    "data de nascimento invalida",
+		},
+		{
+			nome:    "data de nascimento em outro formato",
+			usuario: Usuario{DataNascimento: "10/05/1990", DadosAdicionais: DadosAdicionais{Peso: 70, AtividadeFisica: AtividadeLeve}},
+			erro:    "data de nascimento invalida",
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			recomendacao, erro := NovaRecomendacaoAgua(caso.usuario, hoje, caso.temperatura)
+			if caso.erro != "" {
+				if erro == nil || erro.Error() != caso.erro {
+					t.Fatalf("NovaRecomendacaoAgua() erro = %v, esperado %q", erro, caso.erro)
+				}
+				return
+			}
+			if erro != nil {
+				t.Fatalf("NovaRecomendacaoAgua() erro inesperado: %v", erro)
+			}
+			if recomendacao.Entradas.Idade != caso.idade || recomendacao.MlPorKg != caso.mlPorKg ||
+				recomendacao.AcrescimoTemperatura != caso.acrescimoTemperatura || recomendacao.Recomendado != caso.recomendado {
+				t.Errorf("NovaRecomendacaoAgua() = idade %d, %v ml/kg, +%d ml pela temperatura, %d ml; esperado idade %d, %v ml/kg, +%d ml, %d ml",
+					recomendacao.Entradas.Idade, recomendacao.MlPorKg, recomendacao.AcrescimoTemperatura, recomendacao.Recomendado,
+					caso.idade, caso.mlPorKg, caso.acrescimoTemperatura, caso.recomendado)
+			}
+			if recomendacao.Recomendado%50 != 0 {
+				t.Errorf("Recomendado = %d, esperado multiplo de 50", recomendacao.Recomendado)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package models
+
+// Relatorio traz as médias diárias de um período, considerando só os dias em que o usuário registrou algum consumo
+type Relatorio struct {
+	Dias        int     `json:"dias"` // dias do período com consumo de alimentos ou água
+	Kcal        int     `json:"kcal"`
+	Proteina    float64 `json:"proteina"`
+	Carboidrato float64 `json:"carboidrato"`
+	Gordura     float64 `json:"gordura"`
+	Agua        int     `json:"agua"` // hidratação efetiva: bebidas pelo fator de hidratação mais a água dos alimentos
+}
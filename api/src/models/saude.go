@@ -0,0 +1,154 @@
+package models
+
+import (
+	"API/src/utils"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Aplicativos de saúde de onde consumos podem ser importados
+const (
+	OrigemAppleHealth = "apple_health"
+	OrigemGoogleFit   = "google_fit"
+)
+
+// MaximoImportacaoSaude é a quantidade máxima de registros de água lidos de um arquivo de aplicativo de saúde
+const MaximoImportacaoSaude = 50000
+
+// mililitrosPorUnidadeAppleHealth converte as unidades de volume usadas pelo Apple Health para ml
+var mililitrosPorUnidadeAppleHealth = map[string]float64{
+	"mL":        1,
+	"L":         1000,
+	"dL":        100,
+	"cL":        10,
+	"fl_oz_us":  29.5735,
+	"fl_oz_imp": 28.4131,
+	"cup_us":    236.588,
+	"cup_imp":   284.131,
+	"pt_us":     473.176,
+	"pt_imp":    568.261,
+}
+
+// ValidarOrigem verifica se a origem é um aplicativo de saúde suportado
+func ValidarOrigem(origem string) error {
+	if origem != OrigemAppleHealth && origem != OrigemGoogleFit {
+		return errors.New("origem invalida, valores aceitos: apple_health e google_fit")
+	}
+	return nil
+}
+
+// novoConsumoImportado monta um consumo de água importado com id derivado do registro original,
+// para que importar o mesmo arquivo de novo não duplique consumos
+func novoConsumoImportado(origem string, matricula int, data time.Time, mililitros float64) (ConsumoAgua, error) {
+	consumo := ConsumoAgua{
+		UsuarioMatricula: matricula,
+		Data:             data.UTC().Truncate(time.Microsecond),
+		Quantidade:       int(math.Round(mililitros)),
+		BebidaID:         BebidaAgua,
+		Origem:           origem,
+	}
+	consumo.ID = utils.GerarUUIDDeNome(fmt.Sprintf("%s|%d|%s|%d", origem, matricula, consumo.Data.Format(time.RFC3339Nano), consumo.Quantidade))
+	if consumo.Quantidade <= 0 {
+		return consumo, errors.New("a quantidade de agua deve ser maior que 0")
+	}
+	return consumo, nil
+}
+
+// LerAppleHealth lê os registros HKQuantityTypeIdentifierDietaryWater do export.xml do Apple Health sem carregar o arquivo inteiro.
+// Retorna os consumos lidos e o erro de conversão de cada um, se houver
+func LerAppleHealth(arquivo io.Reader, matricula int) ([]ConsumoAgua, []error, error) {
+	var consumos []ConsumoAgua
+	var errosDeLeitura []error
+	decodificador := xml.NewDecoder(arquivo)
+	for {
+		token, erro := decodificador.Token()
+		if erro == io.EOF {
+			break
+		}
+		if erro != nil {
+			return nil, nil, errors.New("export.xml do apple health mal formado")
+		}
+		elemento, ok := token.(xml.StartElement)
+		if !ok || elemento.Name.Local != "Record" {
+			continue
+		}
+		atributos := make(map[string]string)
+		for _, atributo := range elemento.Attr {
+			atributos[atributo.Name.Local] = atributo.Value
+		}
+		if atributos["type"] != "HKQuantityTypeIdentifierDietaryWater" {
+			continue
+		}
+		if len(consumos) == MaximoImportacaoSaude {
+			return nil, nil, fmt.Errorf("o arquivo deve ter no maximo %d registros de agua", MaximoImportacaoSaude)
+		}
+		consumo, erro := lerRegistroAppleHealth(atributos, matricula)
+		consumos = append(consumos, consumo)
+		errosDeLeitura = append(errosDeLeitura, erro)
+	}
+	return consumos, errosDeLeitura, nil
+}
+
+// lerRegistroAppleHealth converte os atributos de um Record do Apple Health em consumo
+func lerRegistroAppleHealth(atributos map[string]string, matricula int) (ConsumoAgua, error) {
+	data, erro := time.Parse("2006-01-02 15:04:05 -0700", atributos["startDate"])
+	if erro != nil {
+		return ConsumoAgua{}, fmt.Errorf("data %s no formato errado", atributos["startDate"])
+	}
+	mililitrosPorUnidade, existe := mililitrosPorUnidadeAppleHealth[atributos["unit"]]
+	if !existe {
+		return ConsumoAgua{}, fmt.Errorf("unidade %s nao suportada", atributos["unit"])
+	}
+	valor, erro := strconv.ParseFloat(atributos["value"], 64)
+	if erro != nil {
+		return ConsumoAgua{}, errors.New("quantidade invalida")
+	}
+	return novoConsumoImportado(OrigemAppleHealth, matricula, data, valor*mililitrosPorUnidade)
+}
+
+// pontoGoogleFit é um ponto de dados de um arquivo JSON do Google Fit do Takeout
+type pontoGoogleFit struct {
+	DataTypeName   string `json:"dataTypeName"`
+	StartTimeNanos int64  `json:"startTimeNanos"`
+	FitValue       []struct {
+		Value struct {
+			FpVal *float64 `json:"fpVal"`
+		} `json:"value"`
+	} `json:"fitValue"`
+}
+
+// LerGoogleFit lê os pontos com.google.hydration (volume em litros) de um arquivo JSON do Google Fit exportado pelo Takeout.
+// Retorna os consumos lidos e o erro de conversão de cada um, se houver
+func LerGoogleFit(arquivo io.Reader, matricula int) ([]ConsumoAgua, []error, error) {
+	var dados struct {
+		Pontos []pontoGoogleFit `json:"Data Points"`
+	}
+	if erro := json.NewDecoder(arquivo).Decode(&dados); erro != nil {
+		return nil, nil, errors.New("arquivo do google fit mal formado")
+	}
+	var consumos []ConsumoAgua
+	var errosDeLeitura []error
+	for _, ponto := range dados.Pontos {
+		if ponto.DataTypeName != "com.google.hydration" {
+			continue
+		}
+		if len(consumos) == MaximoImportacaoSaude {
+			return nil, nil, fmt.Errorf("o arquivo deve ter no maximo %d registros de agua", MaximoImportacaoSaude)
+		}
+		if len(ponto.FitValue) == 0 || ponto.FitValue[0].Value.FpVal == nil {
+			consumos = append(consumos, ConsumoAgua{})
+			errosDeLeitura = append(errosDeLeitura, errors.New("quantidade invalida"))
+			continue
+		}
+		consumo, erro := novoConsumoImportado(OrigemGoogleFit, matricula, time.Unix(0, ponto.StartTimeNanos), *ponto.FitValue[0].Value.FpVal*1000)
+		consumos = append(consumos, consumo)
+		errosDeLeitura = append(errosDeLeitura, erro)
+	}
+	return consumos, errosDeLeitura, nil
+}
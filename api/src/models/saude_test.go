@@ -0,0 +1,195 @@
+package models
+
+import (
+	"API/src/utils"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resultadoImportacao é o esperado para cada registro de água lido de um arquivo de aplicativo de saúde
+type resultadoImportacao struct {
+	data       time.Time
+	quantidade int
+	erro       string
+}
+
+func conferirImportacao(t *testing.T, origem string, consumos []ConsumoAgua, errosDeLeitura []error, esperados []resultadoImportacao) {
+	t.Helper()
+	if len(consumos) != len(esperados) || len(errosDeLeitura) != len(esperados) {
+		t.Fatalf("lidos %d consumos e %d erros, esperado %d", len(consumos), len(errosDeLeitura), len(esperados))
+	}
+	for i, esperado := range esperados {
+		if esperado.erro != "" {
+			if errosDeLeitura[i] == nil || errosDeLeitura[i].Error() != esperado.erro {
+				t.Errorf("registro %d: erro = %v, esperado %q", i, errosDeLeitura[i], esperado.erro)
+			}
+			continue
+		}
+		if errosDeLeitura[i] != nil {
+			t.Errorf("registro %d: erro inesperado: %v", i, errosDeLeitura[i])
+			continue
+		}
+		consumo := consumos[i]
+		if !consumo.Data.Equal(esperado.data) || consumo.Quantidade != esperado.quantidade {
+			t.Errorf("registro %d: %s %d ml, esperado %s %d ml", i, consumo.Data, consumo.Quantidade, esperado.data, esperado.quantidade)
+		}
+		if consumo.UsuarioMatricula != 1 || consumo.BebidaID != BebidaAgua || consumo.Origem != origem {
+			t.Errorf("registro %d: matricula %d, bebida %d, origem %q", i, consumo.UsuarioMatricula, consumo.BebidaID, consumo.Origem)
+		}
+		// O id vem do registro original para que reimportar o arquivo não duplique consumos
+		id := utils.GerarUUIDDeNome(fmt.Sprintf("%s|1|%s|%d", origem, esperado.data.UTC().Format(time.RFC3339Nano), esperado.quantidade))
+		if consumo.ID != id {
+			t.Errorf("registro %d: id = %s, esperado %s", i, consumo.ID, id)
+		}
+	}
+}
+
+func TestLerAppleHealth(t *testing.T) {
+	casos := []struct {
+		nome      string
+		arquivo   string
+		esperados []resultadoImportacao
+		erro      string
+	}{
+		{
+			nome: "registros de agua em varias unidades",
+			arquivo: `<?xml version="1.0" encoding="UTF-8"?>
+<HealthData locale="pt_BR">
+ <Record type="HKQuantityTypeIdentifierStepCount" unit="count" value="1000" startDate="2024-05-10 08:00:00 -0300"/>
+ <Record type="HKQuantityTypeIdentifierDietaryWater" unit="mL" value="250" startDate="2024-05-10 08:30:00 -0300"/>
+ <Record type="HKQuantityTypeIdentifierDietaryWater" unit="L" value="0.5" startDate="2024-05-10 12:00:00 +0000"/>
+ <Record type="HKQuantityTypeIdentifierDietaryWater" unit="fl_oz_us" value="8" startDate="2024-05-10 20:00:00 -0400"/>
+</HealthData>`,
+			esperados: []resultadoImportacao{
+				{data: time.Date(2024, 5, 10, 11, 30, 0, 0, time.UTC), quantidade: 250},
+				{data: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC), quantidade: 500},
+				{data: time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC), quantidade: 237},
+			},
+		},
+		{
+			nome: "erros de cada registro nao interrompem a leitura",
+			arquivo: `<HealthData>
+ <Record type="HKQuantityTypeIdentifierDietaryWater" unit="mL" value="250" startDate="2024-05-10T08:30:00Z"/>
+ <Record type="HKQuantityTypeIdentifierDietaryWater" unit="gal" value="1" startDate="2024-05-10 08:30:00 -0300"/>
+ <Record type="HKQuantityTypeIdentifierDietaryWater" unit="mL" value="muito" startDate="2024-05-10 08:30:00 -0300"/>
+ <Record type="HKQuantityTypeIdentifierDietaryWater" unit="mL" value="0" startDate="2024-05-10 08:30:00 -0300"/>
+ <Record type="HKQuantityTypeIdentifierDietaryWater" unit="mL" value="300" startDate="2024-05-10 09:00:00 -0300"/>
+</HealthData>`,
+			esperados: []resultadoImportacao{
+				{erro: "data 2024-05-10T08:30:00Z no formato errado"},
+				{erro: "unidade gal nao suportada"},
+				{erro: "quantidade invalida"},
+				{erro: "a quantidade de agua deve ser maior que 0"},
+				{data: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC), quantidade: 300},
+			},
+		},
+		{
+			nome:    "sem registros de agua",
+			arquivo: `<HealthData><Record type="HKQuantityTypeIdentifierStepCount" unit="count" value="1000"/></HealthData>`,
+		},
+		{
+			nome:    "xml mal formado",
+			arquivo: `<HealthData><Record type="HKQuantityTypeIdentifierDietaryWater"`,
+			erro:    "export.xml do apple health mal formado",
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			consumos, errosDeLeitura, erro := LerAppleHealth(strings.NewReader(caso.arquivo), 1)
+			if caso.erro != "" {
+				if erro == nil || erro.Error() != caso.erro {
+					t.Fatalf("LerAppleHealth() erro = %v, esperado %q", erro, caso.erro)
+				}
+				return
+			}
+			if erro != nil {
+				t.Fatalf("LerAppleHealth() erro inesperado: %v", erro)
+			}
+			conferirImportacao(t, OrigemAppleHealth, consumos, errosDeLeitura, caso.esperados)
+		})
+	}
+}
+
+func TestLerGoogleFit(t *testing.T) {
+	casos := []struct {
+		nome      string
+		arquivo   string
+		esperados []resultadoImportacao
+		erro      string
+	}{
+		{
+			nome: "pontos de hidratacao em litros",
+			arquivo: `{"Data Source": "derived:com.google.hydration:com.google.android.gms:merged", "Data Points": [
+				{"dataTypeName": "com.google.step_count.delta", "startTimeNanos": 1715340600000000000, "fitValue": [{"value": {"intVal": 100}}]},
+				{"dataTypeName": "com.google.hydration", "startTimeNanos": 1715340600000000000, "fitValue": [{"value": {"fpVal": 0.25}}]},
+				{"dataTypeName": "com.google.hydration", "startTimeNanos": 1715340600123456789, "fitValue": [{"value": {"fpVal": 0.3333}}]}
+			]}`,
+			esperados: []resultadoImportacao{
+				{data: time.Date(2024, 5, 10, 11, 30, 0, 0, time.UTC), quantidade: 250},
+				// Datas são truncadas em microssegundos, a precisão guardada no banco
+				{data: time.Date(2024, 5, 10, 11, 30, 0, 123456000, time.UTC), quantidade: 333},
+			},
+		},
+		{
+			nome: "pontos sem volume",
+			arquivo: `{"Data Points": [
+				{"dataTypeName": "com.google.hydration", "startTimeNanos": 1715340600000000000, "fitValue": []},
+				{"dataTypeName": "com.google.hydration", "startTimeNanos": 1715340600000000000, "fitValue": [{"value": {"intVal": 1}}]},
+				{"dataTypeName": "com.google.hydration", "startTimeNanos": 1715340600000000000, "fitValue": [{"value": {"fpVal": 0}}]}
+			]}`,
+			esperados: []resultadoImportacao{
+				{erro: "quantidade invalida"},
+				{erro: "quantidade invalida"},
+				{erro: "a quantidade de agua deve ser maior que 0"},
+			},
+		},
+		{
+			nome:    "sem pontos",
+			arquivo: `{}`,
+		},
+		{
+			nome:    "json mal formado",
+			arquivo: `{"Data Points": [`,
+			erro:    "arquivo do google fit mal formado",
+		},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			consumos, errosDeLeitura, erro := LerGoogleFit(strings.NewReader(caso.arquivo), 1)
+			if caso.erro != "" {
+				if erro == nil || erro.Error() != caso.erro {
+					t.Fatalf("LerGoogleFit() erro = %v, esperado %q", erro, caso.erro)
+				}
+				return
+			}
+			if erro != nil {
+				t.Fatalf("LerGoogleFit() erro inesperado: %v", erro)
+			}
+			conferirImportacao(t, OrigemGoogleFit, consumos, errosDeLeitura, caso.esperados)
+		})
+	}
+}
+
+func TestReimportacaoGeraMesmosIds(t *testing.T) {
+	arquivo := `<HealthData><Record type="HKQuantityTypeIdentifierDietaryWater" unit="mL" value="250" startDate="2024-05-10 08:30:00 -0300"/></HealthData>`
+	primeira, _, erro := LerAppleHealth(strings.NewReader(arquivo), 1)
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	segunda, _, erro := LerAppleHealth(strings.NewReader(arquivo), 1)
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	outroUsuario, _, erro := LerAppleHealth(strings.NewReader(arquivo), 2)
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	if primeira[0].ID != segunda[0].ID {
+		t.Errorf("ids diferentes ao reimportar: %s e %s", primeira[0].ID, segunda[0].ID)
+	}
+	if primeira[0].ID == outroUsuario[0].ID {
+		t.Errorf("mesmo id %s para usuarios diferentes", primeira[0].ID)
+	}
+}
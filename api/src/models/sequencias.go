@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type SequenciaAgua struct {
+	Atual            int    `json:"atual"` // dias seguidos atingindo a meta até hoje, ou até ontem se hoje ainda não foi atingida
+	Maior            int    `json:"maior"`
+	InicioAtual      string `json:"inicio_atual,omitempty"` // yyyy-mm-dd
+	MetaAtingidaHoje bool   `json:"meta_atingida_hoje"`
+	InicioUltima     string `json:"-"`
+	FimUltima        string `json:"-"`
+}
+
+// CalcularAtual calcula a sequência atual a partir da sequência mais recente guardada. Ela só continua valendo se terminou hoje ou ontem
+func (s *SequenciaAgua) CalcularAtual(hoje string) {
+	s.Atual, s.InicioAtual, s.MetaAtingidaHoje = 0, "", false
+	dataHoje, erroHoje := time.Parse("2006-01-02", hoje)
+	inicio, erroInicio := time.Parse("2006-01-02", s.InicioUltima)
+	fim, erroFim := time.Parse("2006-01-02", s.FimUltima)
+	if erroHoje != nil || erroInicio != nil || erroFim != nil {
+		return
+	}
+	if !fim.Equal(dataHoje) && !fim.Equal(dataHoje.AddDate(0, 0, -1)) {
+		return
+	}
+	s.Atual = int(fim.Sub(inicio).Hours()/24) + 1
+	s.InicioAtual = s.InicioUltima
+	s.MetaAtingidaHoje = fim.Equal(dataHoje)
+}
@@ -20,8 +20,28 @@ type Usuario struct {
 	DataNascimento string `json:"data_nascimento,omitempty"`
 	Senha          string `json:"senha,omitempty"`
 	DataCriacao    string `json:"data_criacao,omitempty"`
+	AguaMeta       int    `json:"agua_meta,omitempty"`
+	FusoHorario    string `json:"fuso_horario,omitempty"`
+	HoraInicioDia  *int   `json:"hora_inicio_dia,omitempty"`
+	DadosAdicionais
 }
 
+type DadosAdicionais struct {
+	Altura          int     `json:"altura,omitempty"` // cm
+	Peso            float64 `json:"peso,omitempty"`   // kg
+	AtividadeFisica string  `json:"atividade_fisica,omitempty"`
+	HoraAcordar     string  `json:"hora_acordar,omitempty"`
+	HoraDormir      string  `json:"hora_dormir,omitempty"`
+}
+
+// Frequências de atividade física aceitas
+const (
+	AtividadeSedentaria = "S"
+	AtividadeLeve       = "L"
+	AtividadeModerada   = "M"
+	AtividadeIntensa    = "I"
+)
+
 // Validar valida formato e tamanho dos dados, remove espaços em branco e criptografa a senha
 func (u *Usuario) Validar() error {
 	u.Nome = strings.TrimSpace(u.Nome)
@@ -55,6 +75,13 @@ func (u *Usuario) Validar() error {
 	if len(u.Senha) < 2 {
 		return errors.New("senha deve ter pelo menos 2 caracteres")
 	}
+	// Fuso horário é opcional no cadastro
+	if u.FusoHorario == "" {
+		u.FusoHorario = "UTC"
+	}
+	if erro := u.ValidarFusoHorario(); erro != nil {
+		return erro
+	}
 	senhaHash, erro := security.GerarSenhaComHash(u.Senha)
 	if erro != nil {
 		return erro
@@ -112,3 +139,78 @@ func (u *Usuario) ValidarLogin() error {
 	}
 	return nil
 }
+
+// ValidarFusoHorario verifica se o fuso horário é um nome IANA conhecido (ex: America/Sao_Paulo)
+func (u *Usuario) ValidarFusoHorario() error {
+	u.FusoHorario = strings.TrimSpace(u.FusoHorario)
+	if u.FusoHorario == "" {
+		return errors.New("fuso horario faltando")
+	}
+	if _, erro := time.LoadLocation(u.FusoHorario); erro != nil {
+		return errors.New("fuso horario invalido, formato esperado: nome IANA (ex: America/Sao_Paulo)")
+	}
+	return nil
+}
+
+// ValidarHoraInicioDia verifica se a hora de início do dia está presente e entre 0 e 23
+func (u *Usuario) ValidarHoraInicioDia() error {
+	if u.HoraInicioDia == nil {
+		return errors.New("hora de inicio do dia faltando")
+	}
+	if *u.HoraInicioDia < 0 || *u.HoraInicioDia > 23 {
+		return errors.New("hora de inicio do dia deve estar entre 0 e 23")
+	}
+	return nil
+}
+
+// ValidarDadosAdicionais verifica se altura, frequência de atividade física e horas de acordar e dormir (hh:mm:ss ou hh:mm) estão
+// presentes e válidas, como exige a especificação. Peso é opcional, sem ele o peso já cadastrado é mantido
+func (d *DadosAdicionais) ValidarDadosAdicionais() error {
+	if d.Altura == 0 {
+		return errors.New("altura faltando")
+	}
+	if d.Altura < 50 || d.Altura > 272 {
+		return errors.New("altura deve estar entre 50 e 272 cm")
+	}
+	if d.Peso != 0 && (d.Peso < 20 || d.Peso > 500) {
+		return errors.New("peso deve estar entre 20 e 500 kg")
+	}
+	d.AtividadeFisica = strings.ToUpper(strings.TrimSpace(d.AtividadeFisica))
+	switch d.AtividadeFisica {
+	case "":
+		return errors.New("atividade fisica faltando")
+	case AtividadeSedentaria, AtividadeLeve, AtividadeModerada, AtividadeIntensa:
+	default:
+		return errors.New("atividade fisica invalida, valores aceitos: S (sedentario), L (leve), M (moderada) e I (intensa)")
+	}
+	if strings.TrimSpace(d.HoraAcordar) == "" {
+		return errors.New("hora de acordar faltando")
+	}
+	if strings.TrimSpace(d.HoraDormir) == "" {
+		return errors.New("hora de dormir faltando")
+	}
+	var erro error
+	if d.HoraAcordar, erro = validarHora(d.HoraAcordar); erro != nil {
+		return errors.New("hora de acordar invalida, formato esperado: hh:mm:ss")
+	}
+	if d.HoraDormir, erro = validarHora(d.HoraDormir); erro != nil {
+		return errors.New("hora de dormir invalida, formato esperado: hh:mm:ss")
+	}
+	// Dormir depois da meia-noite é permitido, só não pode coincidir com a hora de acordar
+	if d.HoraAcordar == d.HoraDormir {
+		return errors.New("hora de acordar e hora de dormir devem ser diferentes")
+	}
+	return nil
+}
+
+// validarHora aceita hh:mm:ss ou hh:mm e devolve a hora no formato hh:mm:ss
+func validarHora(hora string) (string, error) {
+	hora = strings.TrimSpace(hora)
+	instante, erro := time.Parse("15:04:05", hora)
+	if erro != nil {
+		if instante, erro = time.Parse("15:04", hora); erro != nil {
+			return "", erro
+		}
+	}
+	return instante.Format("15:04:05"), nil
+}
@@ -2,38 +2,154 @@ package repositories
 
 import (
 	"API/src/models"
+	"API/src/utils"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/lib/pq"
 )
 
-// CriarConsumoAgua insere novo consumo no histórico de água
-func CriarConsumoAgua(consumo models.ConsumoAgua, db *sql.DB) error {
-	sqlStatement := `INSERT INTO historico_de_agua (usuario_matricula, data_consumo, quantidade) VALUES ($1, $2, $3)`
-	_, erro := db.Exec(sqlStatement, consumo.UsuarioMatricula, consumo.Data, consumo.Quantidade)
-	if erro != nil {
+// selecaoConsumoAgua seleciona as colunas lidas por escanearConsumoAgua, com a hidratação calculada pelo fator da bebida
+const selecaoConsumoAgua = `SELECT h.id, h.usuario_matricula, h.data_consumo, h.quantidade, h.bebida_id, ROUND(h.quantidade * b.fator_hidratacao)::INT,
+	h.cafeina_mg, h.unidades_alcool, COALESCE(h.recipiente_id, 0), COALESCE(h.origem, '')
+	FROM historico_de_agua h JOIN bebidas b ON b.id = h.bebida_id`
+
+// escanearConsumoAgua lê uma linha selecionada com selecaoConsumoAgua
+func escanearConsumoAgua(linha interface{ Scan(...interface{}) error }, consumo *models.ConsumoAgua) error {
+	return linha.Scan(&consumo.ID, &consumo.UsuarioMatricula, &consumo.Data, &consumo.Quantidade, &consumo.BebidaID, &consumo.Hidratacao, &consumo.CafeinaMg, &consumo.UnidadesAlcool, &consumo.RecipienteID, &consumo.Origem)
+}
+
+// CriarConsumoAgua insere novo consumo no histórico de água, desde que a bebida seja padrão ou do próprio usuário, e calcula sua hidratação.
+// Sem id informado pelo cliente um UUID é gerado
+func CriarConsumoAgua(consumo *models.ConsumoAgua, db Executor) error {
+	if consumo.ID == "" {
+		id, erro := utils.GerarUUID()
+		if erro != nil {
+			return erro
+		}
+		consumo.ID = id
+	}
+	sqlStatement := `INSERT INTO historico_de_agua (id, usuario_matricula, data_consumo, quantidade, bebida_id, cafeina_mg, unidades_alcool, recipiente_id)
+	SELECT $8, $1, $2, $3, id, $5, $6, NULLIF($7, 0) FROM bebidas WHERE id = $4 AND (usuario_matricula IS NULL OR usuario_matricula = $1)
+	RETURNING (SELECT ROUND(quantidade * fator_hidratacao)::INT FROM bebidas WHERE id = bebida_id)`
+	if erro := db.QueryRow(sqlStatement, consumo.UsuarioMatricula, consumo.Data, consumo.Quantidade, consumo.BebidaID, consumo.CafeinaMg, consumo.UnidadesAlcool, consumo.RecipienteID, consumo.ID).Scan(&consumo.Hidratacao); erro != nil {
+		if erro == sql.ErrNoRows {
+			return errors.New("bebida nao encontrada")
+		}
+		var erroPq *pq.Error
+		// 23505 é violação de chave única (id já usado por outro consumo)
+		if errors.As(erro, &erroPq) && erroPq.Code == "23505" {
+			return errors.New("ja existe um consumo com esse id")
+		}
 		return erro
 	}
 	return nil
 }
 
+// ImportarConsumosAgua insere um lote de consumos já validados na transação informada, devolvendo a situação de cada um na mesma ordem.
+// No modo parcial cada consumo tem seu savepoint e falhas não desfazem os demais. No modo transacao a primeira falha desfaz o lote,
+// e quem chamou não deve confirmar a transação
+func ImportarConsumosAgua(consumos []models.ConsumoAgua, opcoes models.OpcoesLote, tx *sql.Tx) ([]models.ResultadoLote, error) {
+	resultados := make([]models.ResultadoLote, len(consumos))
+	var erro error
+	for i := range consumos {
+		if opcoes.Modo == models.ModoParcial {
+			if _, erro = tx.Exec(`SAVEPOINT consumo`); erro != nil {
+				return nil, erro
+			}
+		}
+		status, erro := importarConsumoAgua(&consumos[i], opcoes, tx)
+		resultados[i] = models.ResultadoLote{ID: consumos[i].ID, Status: status}
+		if erro == nil {
+			if opcoes.Modo == models.ModoParcial {
+				if _, erro = tx.Exec(`RELEASE SAVEPOINT consumo`); erro != nil {
+					return nil, erro
+				}
+			}
+			continue
+		}
+		resultados[i].Status, resultados[i].Erro = models.LinhaComErro, erro.Error()
+		if opcoes.Modo == models.ModoParcial {
+			if _, erro = tx.Exec(`ROLLBACK TO SAVEPOINT consumo`); erro != nil {
+				return nil, erro
+			}
+			continue
+		}
+		// Modo transacao: todas as outras linhas são desfeitas
+		for j := range resultados {
+			if j != i {
+				resultados[j] = models.ResultadoLote{ID: consumos[j].ID, Status: models.LinhaRevertida}
+			}
+		}
+		return resultados, nil
+	}
+	return resultados, nil
+}
+
+// importarConsumoAgua insere um consumo de um lote tratando id repetido conforme o conflito escolhido e retorna a situação da linha.
+// Com IgnorarDuplicados um consumo igual (mesma data e quantidade) já registrado também faz a linha ser ignorada. A bebida deve ter sido verificada antes
+func importarConsumoAgua(consumo *models.ConsumoAgua, opcoes models.OpcoesLote, tx *sql.Tx) (string, error) {
+	if consumo.ID == "" {
+		id, erro := utils.GerarUUID()
+		if erro != nil {
+			return "", erro
+		}
+		consumo.ID = id
+	}
+	sqlStatement := `INSERT INTO historico_de_agua (id, usuario_matricula, data_consumo, quantidade, bebida_id, cafeina_mg, unidades_alcool, recipiente_id, origem)
+	SELECT $1, $2, $3, $4, $5, $6, $7, NULLIF($8, 0), NULLIF($9, '')`
+	if opcoes.IgnorarDuplicados {
+		sqlStatement += ` WHERE NOT EXISTS (SELECT 1 FROM historico_de_agua WHERE usuario_matricula = $2 AND data_consumo = $3 AND quantidade = $4)`
+	}
+	switch opcoes.Conflito {
+	case models.ConflitoPular:
+		sqlStatement += ` ON CONFLICT (id) DO NOTHING`
+	case models.ConflitoSobrescrever:
+		// Só sobrescreve consumos do próprio usuário
+		sqlStatement += ` ON CONFLICT (id) DO UPDATE SET data_consumo = EXCLUDED.data_consumo, quantidade = EXCLUDED.quantidade, bebida_id = EXCLUDED.bebida_id,
+		cafeina_mg = EXCLUDED.cafeina_mg, unidades_alcool = EXCLUDED.unidades_alcool, recipiente_id = EXCLUDED.recipiente_id, origem = EXCLUDED.origem
+		WHERE historico_de_agua.usuario_matricula = EXCLUDED.usuario_matricula`
+	}
+	// xmax é 0 em linhas recém inseridas e diferente de 0 em linhas atualizadas pelo ON CONFLICT
+	sqlStatement += ` RETURNING xmax = 0, (SELECT ROUND(quantidade * fator_hidratacao)::INT FROM bebidas WHERE id = bebida_id)`
+	var inserido bool
+	erro := tx.QueryRow(sqlStatement, consumo.ID, consumo.UsuarioMatricula, consumo.Data, consumo.Quantidade, consumo.BebidaID, consumo.CafeinaMg, consumo.UnidadesAlcool, consumo.RecipienteID, consumo.Origem).Scan(&inserido, &consumo.Hidratacao)
+	var erroPq *pq.Error
+	switch {
+	case erro == sql.ErrNoRows && (opcoes.Conflito == models.ConflitoPular || opcoes.IgnorarDuplicados):
+		return models.LinhaIgnorada, nil
+	case erro == sql.ErrNoRows, errors.As(erro, &erroPq) && erroPq.Code == "23505":
+		// No modo sobrescrever nenhuma linha volta quando o id é de consumo de outro usuário
+		return "", errors.New("ja existe um consumo com esse id")
+	case erro != nil:
+		return "", erro
+	case !inserido:
+		return models.LinhaSobrescrita, nil
+	}
+	return models.LinhaCriada, nil
+}
+
 // BuscarConsumoAgua busca um consumo de água do histórico de água
-func BuscarConsumoAgua(matricula int, timestamp time.Time, db *sql.DB) (models.ConsumoAgua, error) {
-	sqlStatement := `SELECT usuario_matricula, data_consumo, quantidade FROM historico_de_agua WHERE usuario_matricula=$1 AND data_consumo=$2`
+func BuscarConsumoAgua(matricula int, id string, db Executor) (models.ConsumoAgua, error) {
+	sqlStatement := selecaoConsumoAgua + ` WHERE h.usuario_matricula=$1 AND h.id=$2`
 	var consumo models.ConsumoAgua
-	if erro := db.QueryRow(sqlStatement, matricula, timestamp).Scan(&consumo.UsuarioMatricula, &consumo.Data, &consumo.Quantidade); erro != nil {
+	if erro := escanearConsumoAgua(db.QueryRow(sqlStatement, matricula, id), &consumo); erro != nil {
 		if erro == sql.ErrNoRows {
-			return models.ConsumoAgua{}, errors.New("usuario logado nao consumiu agua nesse timestamp")
+			return models.ConsumoAgua{}, errors.New("usuario logado nao tem consumo de agua com esse id")
 		}
 		return models.ConsumoAgua{}, erro
 	}
 	return consumo, nil
 }
 
-// AtualizarConsumoAgua atualiza dados de um consumo de água no histórico de água
-func AtualizarConsumoAgua(matricula int, timestamp time.Time, consumo models.ConsumoAgua, db *sql.DB) error {
-	sqlStatement := `UPDATE historico_de_agua SET data_consumo=$1, quantidade=$2 WHERE usuario_matricula=$4 AND data_consumo=$5`
-	result, erro := db.Exec(sqlStatement, consumo.Data, consumo.Quantidade, matricula, timestamp)
+// AtualizarConsumoAgua atualiza dados de um consumo de água no histórico de água. O id do consumo não muda, mesmo alterando sua data
+func AtualizarConsumoAgua(matricula int, id string, consumo models.ConsumoAgua, db Executor) error {
+	sqlStatement := `UPDATE historico_de_agua SET data_consumo=$1, quantidade=$2, bebida_id=$3, cafeina_mg=$6, unidades_alcool=$7, recipiente_id=NULLIF($8, 0)
+	WHERE usuario_matricula=$4 AND id=$5
+	AND EXISTS (SELECT 1 FROM bebidas WHERE id=$3 AND (usuario_matricula IS NULL OR usuario_matricula=$4))`
+	result, erro := db.Exec(sqlStatement, consumo.Data, consumo.Quantidade, consumo.BebidaID, matricula, id, consumo.CafeinaMg, consumo.UnidadesAlcool, consumo.RecipienteID)
 	if erro != nil {
 		return erro
 	}
@@ -43,15 +159,15 @@ func AtualizarConsumoAgua(matricula int, timestamp time.Time, consumo models.Con
 		return erro // Retorna erro se não foi possível verificar as linhas afetadas
 	}
 	if rowsAffected == 0 {
-		return errors.New("consumo de agua nao encontrado para atualizar dados")
+		return errors.New("consumo de agua ou bebida nao encontrados para atualizar dados")
 	}
 	return nil
 }
 
 // DeletarConsumaAgua deleta um consumo de água do histórico de água
-func DeletarConsumoAgua(matricula int, timestamp time.Time, db *sql.DB) error {
-	sqlStatement := `DELETE FROM historico_de_agua WHERE usuario_matricula=$1 AND data_consumo=$2`
-	result, erro := db.Exec(sqlStatement, matricula, timestamp)
+func DeletarConsumoAgua(matricula int, id string, db Executor) error {
+	sqlStatement := `DELETE FROM historico_de_agua WHERE usuario_matricula=$1 AND id=$2`
+	result, erro := db.Exec(sqlStatement, matricula, id)
 	if erro != nil {
 		return erro
 	}
@@ -60,87 +176,194 @@ func DeletarConsumoAgua(matricula int, timestamp time.Time, db *sql.DB) error {
 		return erro // Retorna erro se não foi possível verificar as linhas afetadas
 	}
 	if rowsAffected == 0 {
-		return errors.New("usuario logado nao tem nenhum consumo de agua nesse timestamp")
+		return errors.New("usuario logado nao tem nenhum consumo de agua com esse id")
 	}
 	return nil
 }
 
-// BuscarConsumoAguaDia busca todo consumo de água de um dia
-func BuscarConsumoAguaDia(matricula int, dia string, db *sql.DB) ([]models.ConsumoAgua, error) {
-	sqlStatement := `SELECT usuario_matricula, data_consumo, quantidade FROM historico_de_agua WHERE usuario_matricula = $1 AND data_consumo >= $2 AND data_consumo < $3`
-	rows, err := db.Query(sqlStatement, matricula, dia+"T00:00:00Z", dia+"T23:59:59Z")
+// BuscarConsumoAguaIntervalo busca todo consumo de água entre dois instantes (fim exclusivo), como os limites de um dia, semana ou mês do usuário
+func BuscarConsumoAguaIntervalo(matricula int, inicio, fim time.Time, db *sql.DB) ([]models.ConsumoAgua, error) {
+	sqlStatement := selecaoConsumoAgua + ` WHERE h.usuario_matricula = $1 AND h.data_consumo >= $2 AND h.data_consumo < $3 ORDER BY h.data_consumo, h.id`
+	return buscarConsumosAgua(db, sqlStatement, matricula, inicio, fim)
+}
+
+// BuscarConsumosAguaPeriodo busca uma página de consumos de água de um período usando paginação por cursor (data e id do último consumo da página anterior)
+func BuscarConsumosAguaPeriodo(matricula int, filtro models.FiltroConsumoAgua, db *sql.DB) ([]models.ConsumoAgua, error) {
+	sqlStatement := selecaoConsumoAgua + ` WHERE h.usuario_matricula = $1`
+	argumentos := []interface{}{matricula}
+	// Montando filtros opcionais
+	if !filtro.De.IsZero() {
+		argumentos = append(argumentos, filtro.De)
+		sqlStatement += fmt.Sprintf(" AND h.data_consumo >= $%d", len(argumentos))
+	}
+	if !filtro.Ate.IsZero() {
+		argumentos = append(argumentos, filtro.Ate)
+		sqlStatement += fmt.Sprintf(" AND h.data_consumo < $%d", len(argumentos))
+	}
+	if !filtro.Data.IsZero() {
+		argumentos = append(argumentos, filtro.Data)
+		sqlStatement += fmt.Sprintf(" AND h.data_consumo = $%d", len(argumentos))
+	}
+	// Na ordem decrescente a próxima página está antes do cursor
+	comparador, ordem := ">", "ASC"
+	if filtro.Ordem == "desc" {
+		comparador, ordem = "<", "DESC"
+	}
+	if !filtro.Cursor.IsZero() {
+		// Comparação de tupla desempata consumos com a mesma data pelo id
+		argumentos = append(argumentos, filtro.Cursor, filtro.CursorID)
+		sqlStatement += fmt.Sprintf(" AND (h.data_consumo, h.id) %s ($%d, $%d)", comparador, len(argumentos)-1, len(argumentos))
+	}
+	argumentos = append(argumentos, filtro.Limite)
+	sqlStatement += fmt.Sprintf(" ORDER BY h.usuario_matricula, h.data_consumo %s, h.id %s LIMIT $%d", ordem, ordem, len(argumentos))
+	return buscarConsumosAgua(db, sqlStatement, argumentos...)
+}
+
+// PercorrerConsumosAgua lê os consumos de água de um período (limites opcionais, fim exclusivo) em ordem de data, chamando processar para cada um
+// sem carregar todos na memória. Um erro de processar interrompe a leitura e é retornado
+func PercorrerConsumosAgua(matricula int, de, ate time.Time, processar func(models.ConsumoAgua) error, db *sql.DB) error {
+	sqlStatement := selecaoConsumoAgua + ` WHERE h.usuario_matricula = $1`
+	argumentos := []interface{}{matricula}
+	// Montando filtros opcionais
+	if !de.IsZero() {
+		argumentos = append(argumentos, de)
+		sqlStatement += fmt.Sprintf(" AND h.data_consumo >= $%d", len(argumentos))
+	}
+	if !ate.IsZero() {
+		argumentos = append(argumentos, ate)
+		sqlStatement += fmt.Sprintf(" AND h.data_consumo < $%d", len(argumentos))
+	}
+	sqlStatement += ` ORDER BY h.data_consumo, h.id`
+	rows, err := db.Query(sqlStatement, argumentos...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	// Itera sobre as linhas retornadas
+	for rows.Next() {
+		var consumo models.ConsumoAgua
+		if err := escanearConsumoAgua(rows, &consumo); err != nil {
+			return err
+		}
+		if err := processar(consumo); err != nil {
+			return err
+		}
+	}
+	// Verifica se ocorreu algum erro durante a iteração
+	return rows.Err()
+}
+
+// buscarConsumosAgua executa uma consulta que retorna consumos de água e os lê
+func buscarConsumosAgua(db *sql.DB, sqlStatement string, argumentos ...interface{}) ([]models.ConsumoAgua, error) {
+	rows, err := db.Query(sqlStatement, argumentos...)
 	if err != nil {
 		return []models.ConsumoAgua{}, err
 	}
 	defer rows.Close()
-	var consumosDoDia []models.ConsumoAgua
+	var consumos []models.ConsumoAgua
 	// Itera sobre as linhas retornadas
 	for rows.Next() {
 		var consumo models.ConsumoAgua
-		if err := rows.Scan(&consumo.UsuarioMatricula, &consumo.Data, &consumo.Quantidade); err != nil {
+		if err := escanearConsumoAgua(rows, &consumo); err != nil {
 			return []models.ConsumoAgua{}, err
 		}
-		consumosDoDia = append(consumosDoDia, consumo)
+		consumos = append(consumos, consumo)
 	}
 
 	// Verifica se ocorreu algum erro durante a iteração
 	if err = rows.Err(); err != nil {
 		return []models.ConsumoAgua{}, err
 	}
-	return consumosDoDia, nil
+	return consumos, nil
 }
 
-// BuscarConsumoAguaMes busca todo consumo de água de um mês
-func BuscarConsumoAguaMes(matricula int, mes time.Time, db *sql.DB) ([]models.ConsumoAgua, error) {
-	inicioDoProximoMes := mes.AddDate(0, 1, 0)
-	sqlStatement := `SELECT usuario_matricula, data_consumo, quantidade FROM historico_de_agua WHERE usuario_matricula = $1 AND data_consumo >= $2 AND data_consumo < $3`
-	rows, err := db.Query(sqlStatement, matricula, mes.Format(time.RFC3339), inicioDoProximoMes.Format(time.RFC3339))
+// BuscarTotalConsumoAguaIntervalo soma a quantidade de água consumida e a hidratação efetiva entre dois instantes (fim exclusivo)
+func BuscarTotalConsumoAguaIntervalo(matricula int, inicio, fim time.Time, db *sql.DB) (int, int, error) {
+	sqlStatement := `SELECT COALESCE(SUM(h.quantidade), 0), COALESCE(SUM(ROUND(h.quantidade * b.fator_hidratacao)), 0)::INT
+	FROM historico_de_agua h JOIN bebidas b ON b.id = h.bebida_id
+	WHERE h.usuario_matricula = $1 AND h.data_consumo >= $2 AND h.data_consumo < $3`
+	var total, hidratacao int
+	if erro := db.QueryRow(sqlStatement, matricula, inicio, fim).Scan(&total, &hidratacao); erro != nil {
+		return 0, 0, erro
+	}
+	return total, hidratacao, nil
+}
+
+// BuscarAgregadoAgua soma os consumos de água de um período agrupados em intervalos de hora, dia, semana ou mês no fuso do usuário.
+// A água contida nos alimentos consumidos entra na hidratação, mas não no total nem na contagem de consumos
+func BuscarAgregadoAgua(matricula int, de, ate time.Time, bucket string, calendario utils.Calendario, db *sql.DB) ([]models.AgregadoAgua, error) {
+	// date_trunc é aplicado no horário local do usuário deslocado pela hora de início do dia e o resultado convertido de volta para um instante
+	sqlStatement := `SELECT (date_trunc($4, (f.data AT TIME ZONE $5) - make_interval(hours => $6)) + make_interval(hours => $6)) AT TIME ZONE $5 AS inicio,
+	COALESCE(SUM(f.quantidade), 0), SUM(f.hidratacao)::INT, COALESCE(SUM(f.agua_alimentos), 0)::INT,
+	COUNT(f.quantidade), COALESCE(MIN(f.quantidade), 0), COALESCE(MAX(f.quantidade), 0)
+	FROM (
+		SELECT h.data_consumo AS data, h.quantidade, ROUND(h.quantidade * b.fator_hidratacao) AS hidratacao, NULL::NUMERIC AS agua_alimentos
+		FROM historico_de_agua h JOIN bebidas b ON b.id = h.bebida_id
+		WHERE h.usuario_matricula = $1 AND h.data_consumo >= $2 AND h.data_consumo < $3
+		UNION ALL
+		SELECT c.data, NULL, ROUND(a.agua * c.quantidade / 100), ROUND(a.agua * c.quantidade / 100)
+		FROM alimentos_consumidos c JOIN alimentos a ON a.id = c.alimento_id
+		WHERE c.usuario_matricula = $1 AND c.data >= $2 AND c.data < $3
+	) f
+	GROUP BY inicio ORDER BY inicio`
+	rows, err := db.Query(sqlStatement, matricula, de, ate, bucket, calendario.Local.String(), calendario.HoraInicioDia)
 	if err != nil {
-		return []models.ConsumoAgua{}, err
+		return []models.AgregadoAgua{}, err
 	}
 	defer rows.Close()
-	var consumosDoMes []models.ConsumoAgua
+	var agregados []models.AgregadoAgua
 	// Itera sobre as linhas retornadas
 	for rows.Next() {
-		var consumo models.ConsumoAgua
-		if err := rows.Scan(&consumo.UsuarioMatricula, &consumo.Data, &consumo.Quantidade); err != nil {
-			return []models.ConsumoAgua{}, err
+		var agregado models.AgregadoAgua
+		if err := rows.Scan(&agregado.Inicio, &agregado.Total, &agregado.TotalHidratacao, &agregado.AguaAlimentos, &agregado.Consumos, &agregado.MenorConsumo, &agregado.MaiorConsumo); err != nil {
+			return []models.AgregadoAgua{}, err
 		}
-		consumosDoMes = append(consumosDoMes, consumo)
+		agregados = append(agregados, agregado)
 	}
 
 	// Verifica se ocorreu algum erro durante a iteração
 	if err = rows.Err(); err != nil {
-		return []models.ConsumoAgua{}, err
+		return []models.AgregadoAgua{}, err
 	}
-	return consumosDoMes, nil
+	return agregados, nil
 }
 
-// BuscarConsumoAguaSemana busca todo consumo de água de uma semana
-func BuscarConsumoAguaSemana(matricula int, inicioSemana time.Time, db *sql.DB) ([]models.ConsumoAgua, error) {
-	// Calculando fim da semana
-	fimSemana := inicioSemana.AddDate(0, 0, 7) // Adiciona 7 dias
-
-	// Fazendo consulta
-	sqlStatement := `SELECT usuario_matricula, data_consumo, quantidade FROM historico_de_agua WHERE usuario_matricula = $1 AND data_consumo >= $2 AND data_consumo < $3`
-	rows, err := db.Query(sqlStatement, matricula, inicioSemana.Format(time.RFC3339), fimSemana.Format(time.RFC3339))
+// BuscarTotaisPorHoraAgua soma os consumos de água entre dois instantes (fim exclusivo) por dia da semana e hora no horário local do usuário
+func BuscarTotaisPorHoraAgua(matricula int, de, ate time.Time, calendario utils.Calendario, db *sql.DB) ([]models.TotalHora, error) {
+	sqlStatement := `SELECT EXTRACT(ISODOW FROM data_consumo AT TIME ZONE $4)::INT - 1 AS dia_semana, EXTRACT(HOUR FROM data_consumo AT TIME ZONE $4)::INT AS hora,
+	SUM(quantidade) FROM historico_de_agua
+	WHERE usuario_matricula = $1 AND data_consumo >= $2 AND data_consumo < $3
+	GROUP BY dia_semana, hora`
+	rows, err := db.Query(sqlStatement, matricula, de, ate, calendario.Local.String())
 	if err != nil {
-		return []models.ConsumoAgua{}, err
+		return []models.TotalHora{}, err
 	}
 	defer rows.Close()
-	var consumosDaSemana []models.ConsumoAgua
+	var totais []models.TotalHora
 	// Itera sobre as linhas retornadas
 	for rows.Next() {
-		var consumo models.ConsumoAgua
-		if err := rows.Scan(&consumo.UsuarioMatricula, &consumo.Data, &consumo.Quantidade); err != nil {
-			return []models.ConsumoAgua{}, err
+		var total models.TotalHora
+		if err := rows.Scan(&total.DiaSemana, &total.Hora, &total.Total); err != nil {
+			return []models.TotalHora{}, err
 		}
-		consumosDaSemana = append(consumosDaSemana, consumo)
+		totais = append(totais, total)
 	}
 
 	// Verifica se ocorreu algum erro durante a iteração
 	if err = rows.Err(); err != nil {
-		return []models.ConsumoAgua{}, err
+		return []models.TotalHora{}, err
+	}
+	return totais, nil
+}
+
+// BuscarEstimulantesIntervalo soma cafeína e unidades de álcool consumidas entre dois instantes (fim exclusivo)
+func BuscarEstimulantesIntervalo(matricula int, inicio, fim time.Time, db *sql.DB) (int, float64, error) {
+	sqlStatement := `SELECT COALESCE(SUM(cafeina_mg), 0), COALESCE(SUM(unidades_alcool), 0)
+	FROM historico_de_agua WHERE usuario_matricula = $1 AND data_consumo >= $2 AND data_consumo < $3`
+	var cafeinaMg int
+	var unidadesAlcool float64
+	if erro := db.QueryRow(sqlStatement, matricula, inicio, fim).Scan(&cafeinaMg, &unidadesAlcool); erro != nil {
+		return 0, 0, erro
 	}
-	return consumosDaSemana, nil
+	return cafeinaMg, unidadesAlcool, nil
 }
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"API/src/models"
+	"API/src/utils"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// calculoConsumido calcula os valores de um alimento consumido (c) a partir dos valores por 100 g do alimento (a)
+const calculoConsumido = `c.id, c.alimento_id, c.quantidade, ROUND(a.kcal * c.quantidade / 100)::INT, ROUND(a.proteina * c.quantidade / 100, 1),
+	ROUND(a.gordura * c.quantidade / 100, 1), ROUND(a.carboidrato * c.quantidade / 100, 1), ROUND(a.agua * c.quantidade / 100)::INT, a.nome`
+
+// escanearConsumido lê as colunas de calculoConsumido
+func escanearConsumido(linha interface{ Scan(...interface{}) error }, consumido *models.AlimentoConsumido) error {
+	simples := 1
+	consumido.EhSimples = &simples
+	return linha.Scan(&consumido.ConsumidoID, &consumido.AlimentoID, &consumido.Quantidade, &consumido.Kcal, &consumido.Proteina,
+		&consumido.Gordura, &consumido.Carboidrato, &consumido.Agua, &consumido.Nome)
+}
+
+// SemearAlimentos insere ou atualiza pelo nome os alimentos do catálogo, para que correções no CSV cheguem ao banco
+func SemearAlimentos(alimentos []models.Alimento, db *sql.DB) error {
+	tx, erro := db.Begin()
+	if erro != nil {
+		return erro
+	}
+	defer tx.Rollback()
+	sqlStatement := `INSERT INTO alimentos (nome, kcal, proteina, carboidrato, gordura, agua) VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (nome) DO UPDATE SET kcal = EXCLUDED.kcal, proteina = EXCLUDED.proteina, carboidrato = EXCLUDED.carboidrato,
+	gordura = EXCLUDED.gordura, agua = EXCLUDED.agua`
+	for _, alimento := range alimentos {
+		if _, erro = tx.Exec(sqlStatement, alimento.Nome, alimento.Kcal, alimento.Proteina, alimento.Carboidrato, alimento.Gordura, alimento.Agua); erro != nil {
+			return erro
+		}
+	}
+	return tx.Commit()
+}
+
+// BuscarAlimentos busca os alimentos do catálogo em ordem alfabética, filtrando pelo trecho do nome se informado
+func BuscarAlimentos(nome string, db *sql.DB) ([]models.Alimento, error) {
+	// % e _ digitados pelo usuário são buscados literalmente
+	trecho := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(nome)
+	sqlStatement := `SELECT id, nome, kcal, proteina, carboidrato, gordura, agua FROM alimentos WHERE nome ILIKE '%' || $1 || '%' ORDER BY nome`
+	rows, err := db.Query(sqlStatement, trecho)
+	if err != nil {
+		return []models.Alimento{}, err
+	}
+	defer rows.Close()
+	var alimentos []models.Alimento
+	// Itera sobre as linhas retornadas
+	for rows.Next() {
+		alimento := models.Alimento{EhSimples: 1}
+		if err := rows.Scan(&alimento.ID, &alimento.Nome, &alimento.Kcal, &alimento.Proteina, &alimento.Carboidrato, &alimento.Gordura, &alimento.Agua); err != nil {
+			return []models.Alimento{}, err
+		}
+		alimentos = append(alimentos, alimento)
+	}
+
+	// Verifica se ocorreu algum erro durante a iteração
+	if err = rows.Err(); err != nil {
+		return []models.Alimento{}, err
+	}
+	return alimentos, nil
+}
+
+// CriarConsumoAlimentos insere uma refeição com seus alimentos consumidos e preenche os valores calculados de cada um.
+// Deve ser chamada dentro de uma transação para que a refeição não fique pela metade
+func CriarConsumoAlimentos(consumo *models.ConsumoAlimentos, db Executor) error {
+	sqlStatement := `INSERT INTO consumos_alimentos (usuario_matricula, data) VALUES ($1, $2)`
+	if _, erro := db.Exec(sqlStatement, consumo.UsuarioMatricula, consumo.Data); erro != nil {
+		var erroPq *pq.Error
+		// 23505 é violação de chave única (já existe refeição nesse instante)
+		if errors.As(erro, &erroPq) && erroPq.Code == "23505" {
+			return errors.New("ja existe um consumo de alimentos nesse timestamp")
+		}
+		return erro
+	}
+	sqlStatement = `WITH c AS (
+		INSERT INTO alimentos_consumidos (usuario_matricula, data, alimento_id, quantidade)
+		SELECT $1, $2, id, $4 FROM alimentos WHERE id = $3
+		RETURNING id, alimento_id, quantidade
+	)
+	SELECT ` + calculoConsumido + ` FROM c JOIN alimentos a ON a.id = c.alimento_id`
+	for i := range consumo.Consumidos {
+		consumido := &consumo.Consumidos[i]
+		if erro := escanearConsumido(db.QueryRow(sqlStatement, consumo.UsuarioMatricula, consumo.Data, consumido.AlimentoID, consumido.Quantidade), consumido); erro != nil {
+			if erro == sql.ErrNoRows {
+				return errors.New("alimento nao encontrado")
+			}
+			return erro
+		}
+	}
+	return nil
+}
+
+// DeletarConsumoAlimentos deleta uma refeição do usuário com todos os seus alimentos consumidos
+func DeletarConsumoAlimentos(matricula int, timestamp time.Time, db Executor) error {
+	sqlStatement := `DELETE FROM consumos_alimentos WHERE usuario_matricula=$1 AND data=$2`
+	result, erro := db.Exec(sqlStatement, matricula, timestamp)
+	if erro != nil {
+		return erro
+	}
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("usuario logado nao tem nenhum consumo de alimentos nesse timestamp")
+	}
+	return nil
+}
+
+// BuscarConsumosAlimentosIntervalo busca as refeições do usuário entre dois instantes (fim exclusivo), em ordem cronológica
+func BuscarConsumosAlimentosIntervalo(matricula int, inicio, fim time.Time, db *sql.DB) ([]models.ConsumoAlimentos, error) {
+	sqlStatement := `SELECT c.data, ` + calculoConsumido + ` FROM alimentos_consumidos c JOIN alimentos a ON a.id = c.alimento_id
+	WHERE c.usuario_matricula = $1 AND c.data >= $2 AND c.data < $3 ORDER BY c.data, c.id`
+	rows, err := db.Query(sqlStatement, matricula, inicio, fim)
+	if err != nil {
+		return []models.ConsumoAlimentos{}, err
+	}
+	defer rows.Close()
+	var consumos []models.ConsumoAlimentos
+	// Itera sobre as linhas retornadas, juntando os alimentos de uma mesma refeição
+	for rows.Next() {
+		var data time.Time
+		simples := 1
+		consumido := models.AlimentoConsumido{EhSimples: &simples}
+		if err := rows.Scan(&data, &consumido.ConsumidoID, &consumido.AlimentoID, &consumido.Quantidade, &consumido.Kcal, &consumido.Proteina,
+			&consumido.Gordura, &consumido.Carboidrato, &consumido.Agua, &consumido.Nome); err != nil {
+			return []models.ConsumoAlimentos{}, err
+		}
+		if len(consumos) == 0 || !consumos[len(consumos)-1].Data.Equal(data) {
+			consumos = append(consumos, models.ConsumoAlimentos{Data: data})
+		}
+		ultimo := &consumos[len(consumos)-1]
+		ultimo.Consumidos = append(ultimo.Consumidos, consumido)
+	}
+
+	// Verifica se ocorreu algum erro durante a iteração
+	if err = rows.Err(); err != nil {
+		return []models.ConsumoAlimentos{}, err
+	}
+	return consumos, nil
+}
+
+// BuscarAguaAlimentosIntervalo soma a água contida nos alimentos consumidos pelo usuário entre dois instantes (fim exclusivo), em ml
+func BuscarAguaAlimentosIntervalo(matricula int, inicio, fim time.Time, db *sql.DB) (int, error) {
+	sqlStatement := `SELECT COALESCE(SUM(ROUND(a.agua * c.quantidade / 100)), 0)::INT
+	FROM alimentos_consumidos c JOIN alimentos a ON a.id = c.alimento_id
+	WHERE c.usuario_matricula = $1 AND c.data >= $2 AND c.data < $3`
+	var agua int
+	if erro := db.QueryRow(sqlStatement, matricula, inicio, fim).Scan(&agua); erro != nil {
+		return 0, erro
+	}
+	return agua, nil
+}
+
+// BuscarAguaAlimentosPorDia soma a água contida nos alimentos consumidos pelo usuário entre dois instantes (fim exclusivo) por dia (yyyy-mm-dd) do usuário, em ml
+func BuscarAguaAlimentosPorDia(matricula int, inicio, fim time.Time, calendario utils.Calendario, db *sql.DB) (map[string]int, error) {
+	sqlStatement := `SELECT to_char((c.data AT TIME ZONE $4) - make_interval(hours => $5), 'YYYY-MM-DD') AS dia, SUM(ROUND(a.agua * c.quantidade / 100))::INT
+	FROM alimentos_consumidos c JOIN alimentos a ON a.id = c.alimento_id
+	WHERE c.usuario_matricula = $1 AND c.data >= $2 AND c.data < $3
+	GROUP BY dia`
+	rows, err := db.Query(sqlStatement, matricula, inicio, fim, calendario.Local.String(), calendario.HoraInicioDia)
+	if err != nil {
+		return map[string]int{}, err
+	}
+	defer rows.Close()
+	aguaPorDia := make(map[string]int)
+	// Itera sobre as linhas retornadas
+	for rows.Next() {
+		var dia string
+		var agua int
+		if err := rows.Scan(&dia, &agua); err != nil {
+			return map[string]int{}, err
+		}
+		aguaPorDia[dia] = agua
+	}
+
+	// Verifica se ocorreu algum erro durante a iteração
+	if err = rows.Err(); err != nil {
+		return map[string]int{}, err
+	}
+	return aguaPorDia, nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"API/src/models"
+	"database/sql"
+)
+
+// CriarBebida insere uma bebida personalizada de um usuário no catálogo de bebidas
+func CriarBebida(bebida *models.Bebida, db *sql.DB) error {
+	sqlStatement := `INSERT INTO bebidas (nome, tipo, fator_hidratacao, usuario_matricula) VALUES ($1, $2, $3, $4) RETURNING id`
+	if erro := db.QueryRow(sqlStatement, bebida.Nome, bebida.Tipo, bebida.FatorHidratacao, bebida.UsuarioMatricula).Scan(&bebida.ID); erro != nil {
+		return erro
+	}
+	return nil
+}
+
+// BuscarBebidas busca as bebidas padrão do catálogo e as bebidas personalizadas de um usuário
+func BuscarBebidas(matricula int, db *sql.DB) ([]models.Bebida, error) {
+	sqlStatement := `SELECT id, nome, tipo, fator_hidratacao, COALESCE(usuario_matricula, 0) FROM bebidas WHERE usuario_matricula IS NULL OR usuario_matricula = $1 ORDER BY id`
+	rows, err := db.Query(sqlStatement, matricula)
+	if err != nil {
+		return []models.Bebida{}, err
+	}
+	defer rows.Close()
+	var bebidas []models.Bebida
+	// Itera sobre as linhas retornadas
+	for rows.Next() {
+		var bebida models.Bebida
+		if err := rows.Scan(&bebida.ID, &bebida.Nome, &bebida.Tipo, &bebida.FatorHidratacao, &bebida.UsuarioMatricula); err != nil {
+			return []models.Bebida{}, err
+		}
+		bebidas = append(bebidas, bebida)
+	}
+
+	// Verifica se ocorreu algum erro durante a iteração
+	if err = rows.Err(); err != nil {
+		return []models.Bebida{}, err
+	}
+	return bebidas, nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"API/src/models"
+	"database/sql"
+)
+
+// metricasConquistas calcula o valor atual de cada métrica usada nas regras de conquistas para o usuário $1 a partir do resumo diário,
+// que já segue os dias do calendário do usuário, e das sequências. Novas métricas precisam ser incluídas aqui e no CHECK da tabela conquistas
+const metricasConquistas = `WITH dias AS (
+		SELECT SUM(consumos)::NUMERIC AS consumos, SUM(quantidade) / 1000.0 AS litros,
+		COUNT(*) FILTER (WHERE atingida) AS atingidos, COUNT(*) FILTER (WHERE consumo_manha) AS manhas
+		FROM dias_meta_agua WHERE usuario_matricula = $1
+	), metricas (metrica, valor) AS (
+		SELECT 'consumos_total', COALESCE(consumos, 0) FROM dias
+		UNION ALL
+		SELECT 'litros_total', COALESCE(litros, 0) FROM dias
+		UNION ALL
+		SELECT 'maior_sequencia', COALESCE((SELECT maior FROM sequencias_agua WHERE usuario_matricula = $1), 0)
+		UNION ALL
+		SELECT 'dias_meta_atingida', atingidos FROM dias
+		UNION ALL
+		SELECT 'dias_consumo_manha', manhas FROM dias
+	)`
+
+// AvaliarConquistas guarda as conquistas ativas cujas métricas o usuário já alcançou e ainda não tinha. Conquistas obtidas não são perdidas.
+// Deve ser chamada depois de atualizar o resumo diário e as sequências
+func AvaliarConquistas(matricula int, db Executor) error {
+	sqlStatement := metricasConquistas + `
+	INSERT INTO conquistas_usuarios (usuario_matricula, conquista_id)
+	SELECT $1, c.id FROM conquistas c JOIN metricas m ON m.metrica = c.metrica WHERE c.ativa AND m.valor >= c.limite
+	ON CONFLICT DO NOTHING`
+	if _, erro := db.Exec(sqlStatement, matricula); erro != nil {
+		return erro
+	}
+	return nil
+}
+
+// BuscarConquistas busca as conquistas ativas com o progresso do usuário em cada uma, as já obtidas primeiro
+func BuscarConquistas(matricula int, db *sql.DB) ([]models.Conquista, error) {
+	sqlStatement := metricasConquistas + `
+	SELECT c.id, c.nome, c.descricao, c.metrica, c.limite, ROUND(COALESCE(m.valor, 0), 2), cu.conquistada_em
+	FROM conquistas c
+	LEFT JOIN metricas m ON m.metrica = c.metrica
+	LEFT JOIN conquistas_usuarios cu ON cu.conquista_id = c.id AND cu.usuario_matricula = $1
+	WHERE c.ativa OR cu.conquistada_em IS NOT NULL
+	ORDER BY cu.conquistada_em IS NULL, cu.conquistada_em, c.metrica, c.limite`
+	rows, err := db.Query(sqlStatement, matricula)
+	if err != nil {
+		return []models.Conquista{}, err
+	}
+	defer rows.Close()
+	var conquistas []models.Conquista
+	// Itera sobre as linhas retornadas
+	for rows.Next() {
+		var conquista models.Conquista
+		if err := rows.Scan(&conquista.ID, &conquista.Nome, &conquista.Descricao, &conquista.Metrica, &conquista.Limite, &conquista.Progresso, &conquista.ConquistadaEm); err != nil {
+			return []models.Conquista{}, err
+		}
+		conquista.Conquistada = conquista.ConquistadaEm != nil
+		conquistas = append(conquistas, conquista)
+	}
+
+	// Verifica se ocorreu algum erro durante a iteração
+	if err = rows.Err(); err != nil {
+		return []models.Conquista{}, err
+	}
+	return conquistas, nil
+}
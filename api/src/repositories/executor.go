@@ -0,0 +1,11 @@
+package repositories
+
+import "database/sql"
+
+// Executor é satisfeito por *sql.DB e *sql.Tx, para que as escritas que precisam andar juntas
+// (um consumo e o resumo do seu dia, por exemplo) possam ser feitas na mesma transação
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"API/src/models"
+	"database/sql"
+	"time"
+)
+
+// ReservarChaveIdempotencia tenta reservar uma chave de idempotência para uma nova requisição, descartando antes a chave se ela já expirou.
+// Retorna false se a chave já existia, preenchendo a requisição com o hash e a resposta guardados
+func ReservarChaveIdempotencia(requisicao *models.RequisicaoIdempotente, janela time.Duration, db *sql.DB) (bool, error) {
+	sqlStatement := `DELETE FROM chaves_idempotencia WHERE usuario_matricula = $1 AND chave = $2 AND criada_em < CURRENT_TIMESTAMP - make_interval(secs => $3)`
+	if _, erro := db.Exec(sqlStatement, requisicao.UsuarioMatricula, requisicao.Chave, janela.Seconds()); erro != nil {
+		return false, erro
+	}
+	sqlStatement = `INSERT INTO chaves_idempotencia (usuario_matricula, chave, hash_requisicao) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	result, erro := db.Exec(sqlStatement, requisicao.UsuarioMatricula, requisicao.Chave, requisicao.HashRequisicao)
+	if erro != nil {
+		return false, erro
+	}
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return false, erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 1 {
+		return true, nil
+	}
+	// Chave já usada, buscando resposta guardada
+	sqlStatement = `SELECT hash_requisicao, COALESCE(status_code, 0), COALESCE(corpo, '') FROM chaves_idempotencia WHERE usuario_matricula = $1 AND chave = $2`
+	if erro = db.QueryRow(sqlStatement, requisicao.UsuarioMatricula, requisicao.Chave).Scan(&requisicao.HashRequisicao, &requisicao.StatusCode, &requisicao.Corpo); erro != nil {
+		return false, erro
+	}
+	return false, nil
+}
+
+// SalvarRespostaIdempotencia guarda a resposta da requisição que reservou a chave de idempotência
+func SalvarRespostaIdempotencia(requisicao models.RequisicaoIdempotente, db *sql.DB) error {
+	sqlStatement := `UPDATE chaves_idempotencia SET status_code = $3, corpo = $4 WHERE usuario_matricula = $1 AND chave = $2`
+	if _, erro := db.Exec(sqlStatement, requisicao.UsuarioMatricula, requisicao.Chave, requisicao.StatusCode, requisicao.Corpo); erro != nil {
+		return erro
+	}
+	return nil
+}
+
+// LiberarChaveIdempotencia apaga uma chave de idempotência reservada, permitindo que a requisição seja tentada de novo
+func LiberarChaveIdempotencia(matricula int, chave string, db *sql.DB) error {
+	sqlStatement := `DELETE FROM chaves_idempotencia WHERE usuario_matricula = $1 AND chave = $2`
+	if _, erro := db.Exec(sqlStatement, matricula, chave); erro != nil {
+		return erro
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"API/src/models"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// selecaoMedida são as colunas lidas por escanearMedida
+const selecaoMedida = `SELECT usuario_matricula, data, peso, ombro, peito, braco, antebraco, cintura, quadril, coxa, panturrilha FROM medidas`
+
+// escanearMedida lê uma linha selecionada com selecaoMedida
+func escanearMedida(linha interface{ Scan(...interface{}) error }, medida *models.Medida) error {
+	return linha.Scan(&medida.Dono, &medida.Data, &medida.Peso, &medida.Ombro, &medida.Peito, &medida.Braco, &medida.Antebraco,
+		&medida.Cintura, &medida.Quadril, &medida.Coxa, &medida.Panturrilha)
+}
+
+// CriarMedida insere nova medida do usuário
+func CriarMedida(medida models.Medida, db *sql.DB) error {
+	sqlStatement := `INSERT INTO medidas (usuario_matricula, data, peso, ombro, peito, braco, antebraco, cintura, quadril, coxa, panturrilha)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, erro := db.Exec(sqlStatement, medida.Dono, medida.Data, medida.Peso, medida.Ombro, medida.Peito, medida.Braco, medida.Antebraco,
+		medida.Cintura, medida.Quadril, medida.Coxa, medida.Panturrilha)
+	if erro != nil {
+		var erroPq *pq.Error
+		// 23505 é violação de chave única (já existe medida nesse instante)
+		if errors.As(erro, &erroPq) && erroPq.Code == "23505" {
+			return errors.New("ja existe uma medida nesse timestamp")
+		}
+		return erro
+	}
+	return nil
+}
+
+// BuscarMedidas busca todas medidas do usuário, da mais recente para a mais antiga
+func BuscarMedidas(matricula int, db *sql.DB) ([]models.Medida, error) {
+	sqlStatement := selecaoMedida + ` WHERE usuario_matricula = $1 ORDER BY data DESC`
+	return buscarMedidas(db, sqlStatement, matricula)
+}
+
+// BuscarMedidasIntervalo busca todas medidas do usuário entre dois instantes (fim exclusivo), em ordem cronológica
+func BuscarMedidasIntervalo(matricula int, inicio, fim time.Time, db *sql.DB) ([]models.Medida, error) {
+	sqlStatement := selecaoMedida + ` WHERE usuario_matricula = $1 AND data >= $2 AND data < $3 ORDER BY data`
+	return buscarMedidas(db, sqlStatement, matricula, inicio, fim)
+}
+
+// buscarMedidas executa uma consulta feita com selecaoMedida e lê todas as linhas
+func buscarMedidas(db *sql.DB, sqlStatement string, args ...interface{}) ([]models.Medida, error) {
+	rows, err := db.Query(sqlStatement, args...)
+	if err != nil {
+		return []models.Medida{}, err
+	}
+	defer rows.Close()
+	var medidas []models.Medida
+	// Itera sobre as linhas retornadas
+	for rows.Next() {
+		var medida models.Medida
+		if err := escanearMedida(rows, &medida); err != nil {
+			return []models.Medida{}, err
+		}
+		medidas = append(medidas, medida)
+	}
+
+	// Verifica se ocorreu algum erro durante a iteração
+	if err = rows.Err(); err != nil {
+		return []models.Medida{}, err
+	}
+	return medidas, nil
+}
+
+// BuscarMedida busca uma medida do usuário pela data e hora
+func BuscarMedida(matricula int, timestamp time.Time, db *sql.DB) (models.Medida, error) {
+	sqlStatement := selecaoMedida + ` WHERE usuario_matricula=$1 AND data=$2`
+	var medida models.Medida
+	if erro := escanearMedida(db.QueryRow(sqlStatement, matricula, timestamp), &medida); erro != nil {
+		if erro == sql.ErrNoRows {
+			return models.Medida{}, errors.New("usuario logado nao tem nenhuma medida nesse timestamp")
+		}
+		return models.Medida{}, erro
+	}
+	return medida, nil
+}
+
+// AtualizarMedida atualiza os valores de uma medida do usuário, a data e hora não mudam
+func AtualizarMedida(matricula int, timestamp time.Time, medida models.Medida, db *sql.DB) error {
+	sqlStatement := `UPDATE medidas SET peso=$1, ombro=$2, peito=$3, braco=$4, antebraco=$5, cintura=$6, quadril=$7, coxa=$8, panturrilha=$9
+	WHERE usuario_matricula=$10 AND data=$11`
+	result, erro := db.Exec(sqlStatement, medida.Peso, medida.Ombro, medida.Peito, medida.Braco, medida.Antebraco, medida.Cintura,
+		medida.Quadril, medida.Coxa, medida.Panturrilha, matricula, timestamp)
+	if erro != nil {
+		return erro
+	}
+	// Verifica se alguma linha foi atualizada
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("medida nao encontrada para atualizar dados")
+	}
+	return nil
+}
+
+// DeletarMedida deleta uma medida do usuário
+func DeletarMedida(matricula int, timestamp time.Time, db *sql.DB) error {
+	sqlStatement := `DELETE FROM medidas WHERE usuario_matricula=$1 AND data=$2`
+	result, erro := db.Exec(sqlStatement, matricula, timestamp)
+	if erro != nil {
+		return erro
+	}
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("usuario logado nao tem nenhuma medida nesse timestamp")
+	}
+	return nil
+}
+
+// SincronizarPeso atualiza o peso do usuário depois de uma alteração na medida do instante informado, para que a recomendação de água use o peso atual.
+// O peso só é copiado se essa medida for a mais recente e não for anterior ao peso que o usuário já tem, informado à mão ou por outra medida.
+// Se o peso do usuário veio dessa medida e ela deixou de ser a mais recente ou foi deletada, passa a valer o da mais recente que sobrou
+func SincronizarPeso(matricula int, data time.Time, db *sql.DB) error {
+	sqlStatement := `UPDATE usuarios u SET peso = m.peso, peso_atualizado_em = m.data
+	FROM (SELECT peso, data FROM medidas WHERE usuario_matricula = $1 ORDER BY data DESC LIMIT 1) m
+	WHERE u.matricula = $1 AND ((m.data = $2 AND m.data >= COALESCE(u.peso_atualizado_em, '-infinity')) OR u.peso_atualizado_em = $2)`
+	if _, erro := db.Exec(sqlStatement, matricula, data); erro != nil {
+		return erro
+	}
+	return nil
+}
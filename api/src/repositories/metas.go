@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"API/src/models"
+	"database/sql"
+)
+
+// CriarMetaAgua guarda uma meta de água válida a partir de um dia, substituindo a meta que já começava nesse dia
+func CriarMetaAgua(matricula int, meta models.MetaAgua, db Executor) error {
+	sqlStatement := `INSERT INTO metas_de_agua (usuario_matricula, valida_desde, agua_meta) VALUES ($1, $2, $3)
+	ON CONFLICT (usuario_matricula, valida_desde) DO UPDATE SET agua_meta = EXCLUDED.agua_meta`
+	_, erro := db.Exec(sqlStatement, matricula, meta.ValidaDesde, meta.AguaMeta)
+	if erro != nil {
+		return erro
+	}
+	return nil
+}
+
+// BuscarMetasAgua busca o histórico de metas de água de um usuário ordenado pela data de início
+func BuscarMetasAgua(matricula int, db *sql.DB) ([]models.MetaAgua, error) {
+	sqlStatement := `SELECT TO_CHAR(valida_desde, 'YYYY-MM-DD'), agua_meta FROM metas_de_agua WHERE usuario_matricula = $1 ORDER BY valida_desde`
+	rows, err := db.Query(sqlStatement, matricula)
+	if err != nil {
+		return []models.MetaAgua{}, err
+	}
+	defer rows.Close()
+	var metas []models.MetaAgua
+	// Itera sobre as linhas retornadas
+	for rows.Next() {
+		var meta models.MetaAgua
+		if err := rows.Scan(&meta.ValidaDesde, &meta.AguaMeta); err != nil {
+			return []models.MetaAgua{}, err
+		}
+		metas = append(metas, meta)
+	}
+
+	// Verifica se ocorreu algum erro durante a iteração
+	if err = rows.Err(); err != nil {
+		return []models.MetaAgua{}, err
+	}
+	return metas, nil
+}
+
+// BuscarAguaMeta busca a meta de água que estava em vigor em um dia (yyyy-mm-dd), retornando a meta padrão se não havia nenhuma
+func BuscarAguaMeta(matricula int, dia string, metaPadrao int, db *sql.DB) (int, error) {
+	sqlStatement := `SELECT agua_meta FROM metas_de_agua WHERE usuario_matricula = $1 AND valida_desde <= $2 ORDER BY valida_desde DESC LIMIT 1`
+	var aguaMeta int
+	if erro := db.QueryRow(sqlStatement, matricula, dia).Scan(&aguaMeta); erro != nil {
+		if erro == sql.ErrNoRows {
+			return metaPadrao, nil
+		}
+		return 0, erro
+	}
+	return aguaMeta, nil
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"API/src/models"
+	"database/sql"
+	"errors"
+)
+
+// CriarRecipiente insere um novo recipiente de um usuário
+func CriarRecipiente(recipiente *models.Recipiente, db *sql.DB) error {
+	sqlStatement := `INSERT INTO recipientes (usuario_matricula, nome, capacidade) VALUES ($1, $2, $3) RETURNING id`
+	if erro := db.QueryRow(sqlStatement, recipiente.UsuarioMatricula, recipiente.Nome, recipiente.Capacidade).Scan(&recipiente.ID); erro != nil {
+		return erro
+	}
+	return nil
+}
+
+// BuscarRecipientes busca todos os recipientes de um usuário
+func BuscarRecipientes(matricula int, db *sql.DB) ([]models.Recipiente, error) {
+	sqlStatement := `SELECT id, usuario_matricula, nome, capacidade FROM recipientes WHERE usuario_matricula = $1 ORDER BY id`
+	rows, err := db.Query(sqlStatement, matricula)
+	if err != nil {
+		return []models.Recipiente{}, err
+	}
+	defer rows.Close()
+	var recipientes []models.Recipiente
+	// Itera sobre as linhas retornadas
+	for rows.Next() {
+		var recipiente models.Recipiente
+		if err := rows.Scan(&recipiente.ID, &recipiente.UsuarioMatricula, &recipiente.Nome, &recipiente.Capacidade); err != nil {
+			return []models.Recipiente{}, err
+		}
+		recipientes = append(recipientes, recipiente)
+	}
+
+	// Verifica se ocorreu algum erro durante a iteração
+	if err = rows.Err(); err != nil {
+		return []models.Recipiente{}, err
+	}
+	return recipientes, nil
+}
+
+// BuscarRecipiente busca um recipiente de um usuário
+func BuscarRecipiente(id, matricula int, db *sql.DB) (models.Recipiente, error) {
+	sqlStatement := `SELECT id, usuario_matricula, nome, capacidade FROM recipientes WHERE id=$1 AND usuario_matricula=$2`
+	var recipiente models.Recipiente
+	if erro := db.QueryRow(sqlStatement, id, matricula).Scan(&recipiente.ID, &recipiente.UsuarioMatricula, &recipiente.Nome, &recipiente.Capacidade); erro != nil {
+		if erro == sql.ErrNoRows {
+			return models.Recipiente{}, errors.New("recipiente nao encontrado")
+		}
+		return models.Recipiente{}, erro
+	}
+	return recipiente, nil
+}
+
+// AtualizarRecipiente atualiza nome e capacidade de um recipiente de um usuário
+func AtualizarRecipiente(recipiente models.Recipiente, db *sql.DB) error {
+	sqlStatement := `UPDATE recipientes SET nome=$1, capacidade=$2 WHERE id=$3 AND usuario_matricula=$4`
+	result, erro := db.Exec(sqlStatement, recipiente.Nome, recipiente.Capacidade, recipiente.ID, recipiente.UsuarioMatricula)
+	if erro != nil {
+		return erro
+	}
+	// Verifica se alguma linha foi atualizada
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("recipiente nao encontrado para atualizar dados")
+	}
+	return nil
+}
+
+// DeletarRecipiente deleta um recipiente de um usuário. Consumos registrados com ele mantêm a quantidade
+func DeletarRecipiente(id, matricula int, db *sql.DB) error {
+	sqlStatement := `DELETE FROM recipientes WHERE id=$1 AND usuario_matricula=$2`
+	result, erro := db.Exec(sqlStatement, id, matricula)
+	if erro != nil {
+		return erro
+	}
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("usuario logado nao tem nenhum recipiente com esse id")
+	}
+	return nil
+}
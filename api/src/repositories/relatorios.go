@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"API/src/models"
+	"API/src/utils"
+	"database/sql"
+	"time"
+)
+
+// BuscarRelatorio calcula as médias diárias de kcal, macronutrientes e hidratação do usuário entre dois instantes (fim exclusivo).
+// Os dias seguem o calendário do usuário e só entram na média os que têm algum consumo de alimentos ou água
+func BuscarRelatorio(matricula int, inicio, fim time.Time, calendario utils.Calendario, db *sql.DB) (models.Relatorio, error) {
+	sqlStatement := `WITH consumos AS (
+		SELECT ((h.data_consumo AT TIME ZONE $4) - make_interval(hours => $5))::DATE AS dia,
+		0 AS kcal, 0 AS proteina, 0 AS carboidrato, 0 AS gordura, ROUND(h.quantidade * b.fator_hidratacao) AS agua
+		FROM historico_de_agua h JOIN bebidas b ON b.id = h.bebida_id
+		WHERE h.usuario_matricula = $1 AND h.data_consumo >= $2 AND h.data_consumo < $3
+		UNION ALL
+		SELECT ((c.data AT TIME ZONE $4) - make_interval(hours => $5))::DATE,
+		a.kcal * c.quantidade / 100, a.proteina * c.quantidade / 100, a.carboidrato * c.quantidade / 100, a.gordura * c.quantidade / 100,
+		ROUND(a.agua * c.quantidade / 100)
+		FROM alimentos_consumidos c JOIN alimentos a ON a.id = c.alimento_id
+		WHERE c.usuario_matricula = $1 AND c.data >= $2 AND c.data < $3
+	), dias AS (
+		SELECT dia, SUM(kcal) AS kcal, SUM(proteina) AS proteina, SUM(carboidrato) AS carboidrato, SUM(gordura) AS gordura, SUM(agua) AS agua
+		FROM consumos GROUP BY dia
+	)
+	SELECT COUNT(*), COALESCE(ROUND(AVG(kcal)), 0)::INT, COALESCE(ROUND(AVG(proteina), 2), 0), COALESCE(ROUND(AVG(carboidrato), 2), 0),
+	COALESCE(ROUND(AVG(gordura), 2), 0), COALESCE(ROUND(AVG(agua)), 0)::INT
+	FROM dias`
+	var relatorio models.Relatorio
+	if erro := db.QueryRow(sqlStatement, matricula, inicio, fim, calendario.Local.String(), calendario.HoraInicioDia).Scan(&relatorio.Dias,
+		&relatorio.Kcal, &relatorio.Proteina, &relatorio.Carboidrato, &relatorio.Gordura, &relatorio.Agua); erro != nil {
+		return models.Relatorio{}, erro
+	}
+	return relatorio, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"API/src/models"
+	"API/src/utils"
+	"database/sql"
+	"time"
+)
+
+// atualizarSequenciaAgua recalcula a sequência mais recente e a maior sequência de metas atingidas a partir do resumo diário,
+// sem voltar ao histórico de água. Dias seguidos têm dia menos posição constante, o que agrupa cada sequência
+func atualizarSequenciaAgua(matricula int, db Executor) error {
+	sqlStatement := `WITH dias AS (
+		SELECT dia, dia - (ROW_NUMBER() OVER (ORDER BY dia))::INT AS grupo FROM dias_meta_agua WHERE usuario_matricula = $1 AND atingida
+	), sequencias AS (
+		SELECT MIN(dia) AS inicio, MAX(dia) AS fim, COUNT(*) AS tamanho FROM dias GROUP BY grupo
+	)
+	INSERT INTO sequencias_agua (usuario_matricula, inicio_ultima, fim_ultima, maior)
+	SELECT $1, (SELECT inicio FROM sequencias ORDER BY fim DESC LIMIT 1), (SELECT fim FROM sequencias ORDER BY fim DESC LIMIT 1),
+	COALESCE((SELECT MAX(tamanho) FROM sequencias), 0)
+	ON CONFLICT (usuario_matricula) DO UPDATE SET inicio_ultima = EXCLUDED.inicio_ultima, fim_ultima = EXCLUDED.fim_ultima, maior = EXCLUDED.maior`
+	if _, erro := db.Exec(sqlStatement, matricula); erro != nil {
+		return erro
+	}
+	return nil
+}
+
+// AtualizarDiaMetaAgua refaz o resumo de um dia (yyyy-mm-dd) do usuário depois de uma alteração em seus consumos e atualiza as sequências.
+// Sem meta definida é usada a meta padrão
+func AtualizarDiaMetaAgua(matricula int, dia string, calendario utils.Calendario, metaPadrao int, db Executor) error {
+	data, erro := time.Parse("2006-01-02", dia)
+	if erro != nil {
+		return erro
+	}
+	inicio, fim := calendario.Dia(data)
+	// A hidratação do dia soma bebidas, pelo fator de hidratação, e a água contida nos alimentos consumidos.
+	// Consumos, quantidade e manhã contam só as bebidas e são usados nas conquistas.
+	// Upsert em vez de apagar e inserir, para que escritas simultâneas no mesmo dia não violem a chave
+	sqlStatement := `INSERT INTO dias_meta_agua (usuario_matricula, dia, hidratacao, agua_meta, consumos, quantidade, consumo_manha)
+	SELECT $1, $2::DATE, SUM(f.hidratacao)::INT,
+	COALESCE((SELECT agua_meta FROM metas_de_agua WHERE usuario_matricula = $1 AND valida_desde <= $2::DATE ORDER BY valida_desde DESC LIMIT 1), $5),
+	COUNT(f.quantidade), COALESCE(SUM(f.quantidade), 0), COALESCE(BOOL_OR(f.manha), FALSE)
+	FROM (
+		SELECT h.quantidade, ROUND(h.quantidade * b.fator_hidratacao) AS hidratacao, EXTRACT(HOUR FROM h.data_consumo AT TIME ZONE $6) BETWEEN 5 AND 11 AS manha
+		FROM historico_de_agua h JOIN bebidas b ON b.id = h.bebida_id
+		WHERE h.usuario_matricula = $1 AND h.data_consumo >= $3 AND h.data_consumo < $4
+		UNION ALL
+		SELECT NULL::INT, ROUND(a.agua * c.quantidade / 100), NULL::BOOLEAN FROM alimentos_consumidos c JOIN alimentos a ON a.id = c.alimento_id
+		WHERE c.usuario_matricula = $1 AND c.data >= $3 AND c.data < $4
+	) f
+	HAVING COUNT(*) > 0
+	ON CONFLICT (usuario_matricula, dia) DO UPDATE SET hidratacao = EXCLUDED.hidratacao, agua_meta = EXCLUDED.agua_meta,
+	consumos = EXCLUDED.consumos, quantidade = EXCLUDED.quantidade, consumo_manha = EXCLUDED.consumo_manha`
+	if _, erro := db.Exec(sqlStatement, matricula, dia, inicio, fim, metaPadrao, calendario.Local.String()); erro != nil {
+		return erro
+	}
+	// Dia que ficou sem nenhum consumo sai do resumo
+	sqlStatement = `DELETE FROM dias_meta_agua WHERE usuario_matricula = $1 AND dia = $2
+	AND NOT EXISTS (SELECT 1 FROM historico_de_agua WHERE usuario_matricula = $1 AND data_consumo >= $3 AND data_consumo < $4)
+	AND NOT EXISTS (SELECT 1 FROM alimentos_consumidos WHERE usuario_matricula = $1 AND data >= $3 AND data < $4)`
+	if _, erro := db.Exec(sqlStatement, matricula, dia, inicio, fim); erro != nil {
+		return erro
+	}
+	return atualizarSequenciaAgua(matricula, db)
+}
+
+// AtualizarMetasDiasAgua atualiza a meta de cada dia do resumo depois de uma mudança nas metas do usuário e atualiza as sequências
+func AtualizarMetasDiasAgua(matricula int, metaPadrao int, db Executor) error {
+	sqlStatement := `UPDATE dias_meta_agua d SET agua_meta = COALESCE((SELECT m.agua_meta FROM metas_de_agua m
+		WHERE m.usuario_matricula = d.usuario_matricula AND m.valida_desde <= d.dia ORDER BY m.valida_desde DESC LIMIT 1), $2)
+	WHERE d.usuario_matricula = $1`
+	if _, erro := db.Exec(sqlStatement, matricula, metaPadrao); erro != nil {
+		return erro
+	}
+	return atualizarSequenciaAgua(matricula, db)
+}
+
+// RecalcularDiasMetaAgua refaz todo o resumo diário a partir do histórico de água e dos alimentos consumidos, para quando os limites dos dias mudam
+// (fuso horário ou hora de início do dia) ou muitos consumos são importados de uma vez, e atualiza as sequências
+func RecalcularDiasMetaAgua(matricula int, calendario utils.Calendario, metaPadrao int, db Executor) error {
+	sqlStatement := `DELETE FROM dias_meta_agua WHERE usuario_matricula = $1`
+	if _, erro := db.Exec(sqlStatement, matricula); erro != nil {
+		return erro
+	}
+	sqlStatement = `INSERT INTO dias_meta_agua (usuario_matricula, dia, hidratacao, agua_meta, consumos, quantidade, consumo_manha)
+	SELECT $1, d.dia, d.hidratacao,
+	COALESCE((SELECT agua_meta FROM metas_de_agua WHERE usuario_matricula = $1 AND valida_desde <= d.dia ORDER BY valida_desde DESC LIMIT 1), $4),
+	d.consumos, d.quantidade, d.consumo_manha
+	FROM (
+		SELECT ((f.data AT TIME ZONE $2) - make_interval(hours => $3))::DATE AS dia, SUM(f.hidratacao)::INT AS hidratacao,
+		COUNT(f.quantidade) AS consumos, COALESCE(SUM(f.quantidade), 0) AS quantidade, COALESCE(BOOL_OR(f.manha), FALSE) AS consumo_manha
+		FROM (
+			SELECT h.data_consumo AS data, h.quantidade, ROUND(h.quantidade * b.fator_hidratacao) AS hidratacao,
+			EXTRACT(HOUR FROM h.data_consumo AT TIME ZONE $2) BETWEEN 5 AND 11 AS manha
+			FROM historico_de_agua h JOIN bebidas b ON b.id = h.bebida_id
+			WHERE h.usuario_matricula = $1
+			UNION ALL
+			SELECT c.data, NULL::INT, ROUND(a.agua * c.quantidade / 100), NULL::BOOLEAN FROM alimentos_consumidos c JOIN alimentos a ON a.id = c.alimento_id
+			WHERE c.usuario_matricula = $1
+		) f
+		GROUP BY 1
+	) d
+	ON CONFLICT (usuario_matricula, dia) DO UPDATE SET hidratacao = EXCLUDED.hidratacao, agua_meta = EXCLUDED.agua_meta,
+	consumos = EXCLUDED.consumos, quantidade = EXCLUDED.quantidade, consumo_manha = EXCLUDED.consumo_manha`
+	if _, erro := db.Exec(sqlStatement, matricula, calendario.Local.String(), calendario.HoraInicioDia, metaPadrao); erro != nil {
+		return erro
+	}
+	return atualizarSequenciaAgua(matricula, db)
+}
+
+// BuscarSequenciaAgua busca a sequência mais recente e a maior sequência de metas de água atingidas de um usuário
+func BuscarSequenciaAgua(matricula int, db *sql.DB) (models.SequenciaAgua, error) {
+	sqlStatement := `SELECT COALESCE(TO_CHAR(inicio_ultima, 'YYYY-MM-DD'), ''), COALESCE(TO_CHAR(fim_ultima, 'YYYY-MM-DD'), ''), maior
+	FROM sequencias_agua WHERE usuario_matricula = $1`
+	var sequencia models.SequenciaAgua
+	if erro := db.QueryRow(sqlStatement, matricula).Scan(&sequencia.InicioUltima, &sequencia.FimUltima, &sequencia.Maior); erro != nil {
+		// Usuário que ainda não registrou consumos não tem sequência
+		if erro == sql.ErrNoRows {
+			return models.SequenciaAgua{}, nil
+		}
+		return models.SequenciaAgua{}, erro
+	}
+	return sequencia, nil
+}
@@ -2,14 +2,15 @@ package repositories
 
 import (
 	"API/src/models"
+	"API/src/utils"
 	"database/sql"
 	"errors"
 )
 
 // CriarUsuario insere um novo usuario no banco de dados
 func CriarUsuario(usuario *models.Usuario, db *sql.DB) error {
-	sqlStatement := `INSERT INTO usuarios (nome, sobrenome, apelido, celular, email, sexo, data_nascimento, senha) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING matricula`
-	if erro := db.QueryRow(sqlStatement, usuario.Nome, usuario.Sobrenome, usuario.Apelido, usuario.Celular, usuario.Email, usuario.Sexo, usuario.DataNascimento, usuario.Senha).Scan(&usuario.Matricula); erro != nil {
+	sqlStatement := `INSERT INTO usuarios (nome, sobrenome, apelido, celular, email, sexo, data_nascimento, senha, fuso_horario) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING matricula`
+	if erro := db.QueryRow(sqlStatement, usuario.Nome, usuario.Sobrenome, usuario.Apelido, usuario.Celular, usuario.Email, usuario.Sexo, usuario.DataNascimento, usuario.Senha, usuario.FusoHorario).Scan(&usuario.Matricula); erro != nil {
 		return erro
 	}
 	return nil
@@ -28,11 +29,17 @@ func BuscarMatriculaESenhaPorEmail(email string, db *sql.DB) (models.Usuario, er
 	return usuario, nil
 }
 
-// BuscarLogado busca dados exceto a senha de um usuário pela matrícula
-func BuscarLogado(matricula int, db *sql.DB) (models.Usuario, error) {
-	sqlStatement := `SELECT matricula, nome, sobrenome, apelido, celular, email, sexo, data_nascimento, data_criacao FROM usuarios WHERE matricula=$1`
+// BuscarLogado busca dados exceto a senha de um usuário pela matrícula, com a meta de água em vigor hoje ou a meta padrão
+func BuscarLogado(matricula int, metaPadrao int, db *sql.DB) (models.Usuario, error) {
+	sqlStatement := `SELECT matricula, nome, sobrenome, apelido, celular, email, sexo, data_nascimento, data_criacao, fuso_horario, hora_inicio_dia,
+	COALESCE((SELECT agua_meta FROM metas_de_agua WHERE usuario_matricula=matricula
+		AND valida_desde <= ((CURRENT_TIMESTAMP AT TIME ZONE fuso_horario) - make_interval(hours => hora_inicio_dia))::date
+		ORDER BY valida_desde DESC LIMIT 1), $2),
+	COALESCE(altura, 0), COALESCE(peso, 0), COALESCE(atividade_fisica, ''), COALESCE(hora_acordar::TEXT, ''), COALESCE(hora_dormir::TEXT, '')
+	FROM usuarios WHERE matricula=$1`
 	var usuario models.Usuario
-	if erro := db.QueryRow(sqlStatement, matricula).Scan(&usuario.Matricula, &usuario.Nome, &usuario.Sobrenome, &usuario.Apelido, &usuario.Celular, &usuario.Email, &usuario.Sexo, &usuario.DataNascimento, &usuario.DataCriacao); erro != nil {
+	if erro := db.QueryRow(sqlStatement, matricula, metaPadrao).Scan(&usuario.Matricula, &usuario.Nome, &usuario.Sobrenome, &usuario.Apelido, &usuario.Celular, &usuario.Email, &usuario.Sexo, &usuario.DataNascimento, &usuario.DataCriacao, &usuario.FusoHorario, &usuario.HoraInicioDia, &usuario.AguaMeta,
+		&usuario.Altura, &usuario.Peso, &usuario.AtividadeFisica, &usuario.HoraAcordar, &usuario.HoraDormir); erro != nil {
 		if erro == sql.ErrNoRows {
 			return models.Usuario{}, errors.New("matricula nao encontrada")
 		}
@@ -125,3 +132,122 @@ func AtualizarSenha(senha string, matricula int, db *sql.DB) error {
 	}
 	return nil
 }
+
+// AtualizarMetas guarda as metas de um usuário como válidas a partir de hoje no seu fuso horário
+func AtualizarMetas(metas models.Metas, matricula int, db Executor) error {
+	calendario, erro := BuscarCalendario(matricula, db)
+	if erro != nil {
+		return erro
+	}
+	return CriarMetaAgua(matricula, models.MetaAgua{ValidaDesde: calendario.Hoje(), AguaMeta: *metas.AguaMeta}, db)
+}
+
+// AtualizarFusoHorario atualiza fuso horário na tabela usuários
+func AtualizarFusoHorario(dados models.Usuario, db Executor) error {
+	sqlStatement := `UPDATE usuarios SET fuso_horario=$1 WHERE matricula=$2`
+	result, erro := db.Exec(sqlStatement, dados.FusoHorario, dados.Matricula)
+	if erro != nil {
+		return erro
+	}
+	// Verifica se alguma linha foi atualizada
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("usuario nao encontrado para atualizar dados")
+	}
+	return nil
+}
+
+// AtualizarDadosAdicionais atualiza altura, peso, atividade física e horas de acordar e dormir na tabela usuários. Sem peso o atual é mantido,
+// e com peso ele passa a valer a partir de agora, para que medidas anteriores não o sobrescrevam
+func AtualizarDadosAdicionais(dados models.DadosAdicionais, matricula int, db *sql.DB) error {
+	sqlStatement := `UPDATE usuarios SET altura=$1, peso=COALESCE(NULLIF($2::NUMERIC, 0), peso),
+	peso_atualizado_em=CASE WHEN $2::NUMERIC > 0 THEN CURRENT_TIMESTAMP ELSE peso_atualizado_em END, atividade_fisica=$3, hora_acordar=$4, hora_dormir=$5 WHERE matricula=$6`
+	result, erro := db.Exec(sqlStatement, dados.Altura, dados.Peso, dados.AtividadeFisica, dados.HoraAcordar, dados.HoraDormir, matricula)
+	if erro != nil {
+		return erro
+	}
+	// Verifica se alguma linha foi atualizada
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("usuario nao encontrado para atualizar dados")
+	}
+	return nil
+}
+
+// AtualizarHoraInicioDia atualiza hora de início do dia na tabela usuários
+func AtualizarHoraInicioDia(dados models.Usuario, db Executor) error {
+	sqlStatement := `UPDATE usuarios SET hora_inicio_dia=$1 WHERE matricula=$2`
+	result, erro := db.Exec(sqlStatement, *dados.HoraInicioDia, dados.Matricula)
+	if erro != nil {
+		return erro
+	}
+	// Verifica se alguma linha foi atualizada
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("usuario nao encontrado para atualizar dados")
+	}
+	return nil
+}
+
+// BuscarCalendario busca o fuso horário e a hora de início do dia de um usuário e monta seu calendário
+func BuscarCalendario(matricula int, db Executor) (utils.Calendario, error) {
+	sqlStatement := `SELECT fuso_horario, hora_inicio_dia FROM usuarios WHERE matricula=$1`
+	var fusoHorario string
+	var horaInicioDia int
+	if erro := db.QueryRow(sqlStatement, matricula).Scan(&fusoHorario, &horaInicioDia); erro != nil {
+		if erro == sql.ErrNoRows {
+			return utils.Calendario{}, errors.New("usuario com essa matricula nao encontrado")
+		}
+		return utils.Calendario{}, erro
+	}
+	return utils.NovoCalendario(fusoHorario, horaInicioDia)
+}
+
+// AtualizarLimites atualiza limites diários de cafeína e álcool na tabela usuários
+func AtualizarLimites(limites models.Limites, matricula int, db *sql.DB) error {
+	sqlStatement := `UPDATE usuarios SET limite_cafeina_mg=$1, limite_unidades_alcool=$2 WHERE matricula=$3`
+	result, erro := db.Exec(sqlStatement, limites.LimiteCafeinaMg, limites.LimiteUnidadesAlcool, matricula)
+	if erro != nil {
+		return erro
+	}
+	// Verifica se alguma linha foi atualizada
+	rowsAffected, erro := result.RowsAffected()
+	if erro != nil {
+		return erro // Retorna erro se não foi possível verificar as linhas afetadas
+	}
+	if rowsAffected == 0 {
+		return errors.New("usuario nao encontrado para atualizar dados")
+	}
+	return nil
+}
+
+// BuscarLimites busca limites diários de cafeína e álcool de um usuário
+func BuscarLimites(matricula int, db *sql.DB) (models.Limites, error) {
+	sqlStatement := `SELECT limite_cafeina_mg, limite_unidades_alcool FROM usuarios WHERE matricula=$1`
+	var limiteCafeinaMg sql.NullInt64
+	var limiteUnidadesAlcool sql.NullFloat64
+	if erro := db.QueryRow(sqlStatement, matricula).Scan(&limiteCafeinaMg, &limiteUnidadesAlcool); erro != nil {
+		if erro == sql.ErrNoRows {
+			return models.Limites{}, errors.New("usuario com essa matricula nao encontrado")
+		}
+		return models.Limites{}, erro
+	}
+	var limites models.Limites
+	if limiteCafeinaMg.Valid {
+		valor := int(limiteCafeinaMg.Int64)
+		limites.LimiteCafeinaMg = &valor
+	}
+	if limiteUnidadesAlcool.Valid {
+		limites.LimiteUnidadesAlcool = &limiteUnidadesAlcool.Float64
+	}
+	return limites, nil
+}
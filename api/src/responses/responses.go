@@ -29,3 +29,10 @@ func RespostaDeSucesso(w http.ResponseWriter, statusCode int, dados interface{})
 		}
 	}
 }
+
+// RespostaDeSucessoComTipo envia uma resposta de sucesso já codificada com o tipo de conteúdo informado
+func RespostaDeSucessoComTipo(w http.ResponseWriter, statusCode int, tipo string, dados []byte) {
+	w.Header().Set("Content-Type", tipo)
+	w.WriteHeader(statusCode)
+	w.Write(dados)
+}
@@ -13,13 +13,27 @@ func AguaRouter() chi.Router {
 
 	r.Use(middlewares.Autenticar)
 
-	r.Post("/", controllers.CriarConsumoAgua)
+	r.With(middlewares.Idempotencia).Post("/", controllers.CriarConsumoAgua)
 
-	r.Get("/{timestamp}", controllers.BuscarConsumoAgua)
+	r.With(middlewares.Idempotencia).Post("/agora", controllers.CriarConsumoAguaAgora)
 
-	r.Put("/{timestamp}", controllers.AtualizarConsumoAgua)
+	r.With(middlewares.Idempotencia).Post("/lote", controllers.ImportarConsumosAgua)
 
-	r.Delete("/{timestamp}", controllers.DeletarConsumoAgua)
+	r.With(middlewares.Idempotencia).Post("/importar", controllers.ImportarConsumosAguaCSV)
+
+	r.Post("/importar/{origem}", controllers.ImportarConsumosAguaSaude)
+
+	r.Get("/exportar", controllers.ExportarConsumosAgua)
+
+	r.Get("/fhir", controllers.ExportarConsumosAguaFHIR)
+
+	r.Get("/", controllers.BuscarConsumosAgua)
+
+	r.Get("/{id}", controllers.BuscarConsumoAgua)
+
+	r.Put("/{id}", controllers.AtualizarConsumoAgua)
+
+	r.Delete("/{id}", controllers.DeletarConsumoAgua)
 
 	r.Get("/dia/{dia}", controllers.BuscarConsumoAguaDia)
 
@@ -27,5 +41,37 @@ func AguaRouter() chi.Router {
 
 	r.Get("/semana/{ano}/{semana}", controllers.BuscarConsumoAguaSemana)
 
+	r.Get("/progresso/{dia}", controllers.BuscarProgressoAgua)
+
+	r.Post("/metas", controllers.CriarMetaAgua)
+
+	r.Get("/metas", controllers.BuscarMetasAgua)
+
+	r.Get("/agregado", controllers.BuscarAgregadoAgua)
+
+	r.Get("/estatisticas", controllers.BuscarEstatisticasAgua)
+
+	r.Get("/mapa-de-calor", controllers.BuscarMapaDeCalorAgua)
+
+	r.Get("/sequencia", controllers.BuscarSequenciaAgua)
+
+	r.Get("/recomendacao", controllers.BuscarRecomendacaoAgua)
+
+	r.Post("/bebidas", controllers.CriarBebida)
+
+	r.Get("/bebidas", controllers.BuscarBebidas)
+
+	r.Get("/estimulantes/{dia}", controllers.BuscarEstimulantesDia)
+
+	r.Post("/recipientes", controllers.CriarRecipiente)
+
+	r.Get("/recipientes", controllers.BuscarRecipientes)
+
+	r.Get("/recipientes/{id}", controllers.BuscarRecipiente)
+
+	r.Put("/recipientes/{id}", controllers.AtualizarRecipiente)
+
+	r.Delete("/recipientes/{id}", controllers.DeletarRecipiente)
+
 	return r
 }
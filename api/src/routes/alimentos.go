@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"API/src/controllers"
+	"API/src/middlewares"
+
+	"github.com/go-chi/chi"
+)
+
+// AlimentosRouter retorna roteador de rotas /alimentos
+func AlimentosRouter() chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middlewares.Autenticar)
+
+	r.Get("/", controllers.BuscarAlimentos)
+
+	return r
+}
+
+// ConsumoRouter retorna roteador de rotas /consumo, das refeições do usuário
+func ConsumoRouter() chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middlewares.Autenticar)
+
+	r.With(middlewares.Idempotencia).Post("/", controllers.CriarConsumoAlimentos)
+
+	r.Delete("/{timestamp}", controllers.DeletarConsumoAlimentos)
+
+	r.Get("/dia/{dia}", controllers.BuscarConsumoAlimentosDia)
+
+	r.Get("/mes/{mes}", controllers.BuscarConsumoAlimentosMes)
+
+	r.Get("/semana/{ano}/{semana}", controllers.BuscarConsumoAlimentosSemana)
+
+	return r
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"API/src/controllers"
+	"API/src/middlewares"
+
+	"github.com/go-chi/chi"
+)
+
+// MedidasRouter retorna roteador de rotas /medidas
+func MedidasRouter() chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middlewares.Autenticar)
+
+	r.With(middlewares.Idempotencia).Post("/", controllers.CriarMedida)
+
+	r.Get("/", controllers.BuscarMedidas)
+
+	r.Get("/{timestamp}", controllers.BuscarMedida)
+
+	r.Put("/{timestamp}", controllers.AtualizarMedida)
+
+	r.Delete("/{timestamp}", controllers.DeletarMedida)
+
+	r.Get("/dia/{dia}", controllers.BuscarMedidasDia)
+
+	r.Get("/mes/{mes}", controllers.BuscarMedidasMes)
+
+	r.Get("/semana/{ano}/{semana}", controllers.BuscarMedidasSemana)
+
+	return r
+}
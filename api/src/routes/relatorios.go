@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"API/src/controllers"
+	"API/src/middlewares"
+
+	"github.com/go-chi/chi"
+)
+
+// RelatoriosRouter retorna roteador de rotas /relatorios
+func RelatoriosRouter() chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middlewares.Autenticar)
+
+	r.Get("/relatorio-mensal/{mes}", controllers.GerarRelatorioMensal)
+
+	r.Get("/relatorio-semanal/{ano}/{semana}", controllers.GerarRelatorioSemanal)
+
+	return r
+}
@@ -20,5 +20,19 @@ func Rotear() chi.Router {
 
 	r.Mount("/agua", AguaRouter())
 
+	// /medidas
+
+	r.Mount("/medidas", MedidasRouter())
+
+	// /alimentos e /consumo
+
+	r.Mount("/alimentos", AlimentosRouter())
+
+	r.Mount("/consumo", ConsumoRouter())
+
+	// /relatorios
+
+	r.Mount("/relatorios", RelatoriosRouter())
+
 	return r
 }
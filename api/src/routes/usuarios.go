@@ -11,20 +11,38 @@ import (
 func UsuariosRouter() chi.Router {
 	r := chi.NewRouter()
 
-	r.Post("/", controllers.CriarUsuario)
+	r.With(middlewares.Idempotencia).Post("/", controllers.CriarUsuario)
 
 	r.Group(func(r chi.Router) {
 		r.Use(middlewares.Autenticar)
 
 		r.Get("/me", controllers.BuscarLogado)
 
-		r.Patch("/dados-da-conta", controllers.AtualizarConta)
+		r.Get("/me/conquistas", controllers.BuscarConquistas)
 
-		r.Patch("/celular", controllers.AtualizarCelular)
+		// Troca de senha fica fora da idempotência para que nenhum dado derivado da senha seja guardado
+		r.Patch("/senha", controllers.AtualizarSenha)
 
-		r.Patch("/email", controllers.AtualizarEmail)
+		// Rotas de escrita aceitam o cabeçalho Idempotency-Key
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.Idempotencia)
 
-		r.Patch("/senha", controllers.AtualizarSenha)
+			r.Patch("/dados-adicionais", controllers.AtualizarDadosAdicionais)
+
+			r.Patch("/dados-da-conta", controllers.AtualizarConta)
+
+			r.Patch("/celular", controllers.AtualizarCelular)
+
+			r.Patch("/email", controllers.AtualizarEmail)
+
+			r.Patch("/metas", controllers.AtualizarMetas)
+
+			r.Patch("/fuso-horario", controllers.AtualizarFusoHorario)
+
+			r.Patch("/inicio-do-dia", controllers.AtualizarHoraInicioDia)
+
+			r.Patch("/limites", controllers.AtualizarLimites)
+		})
 	})
 
 	return r
@@ -0,0 +1,53 @@
+package utils
+
+import "time"
+
+// Calendario guarda o fuso horário de um usuário e a hora em que seu dia começa, definindo onde começam e terminam seus dias
+type Calendario struct {
+	Local         *time.Location
+	HoraInicioDia int
+}
+
+// NovoCalendario cria um calendário a partir do nome IANA de um fuso horário (ex: America/Sao_Paulo) e da hora de início do dia (0-23)
+func NovoCalendario(fusoHorario string, horaInicioDia int) (Calendario, error) {
+	local, erro := time.LoadLocation(fusoHorario)
+	if erro != nil {
+		return Calendario{}, erro
+	}
+	return Calendario{Local: local, HoraInicioDia: horaInicioDia}, nil
+}
+
+// InicioDoDia retorna o instante em que começa um dia no fuso do usuário
+func (c Calendario) InicioDoDia(ano int, mes time.Month, dia int) time.Time {
+	return time.Date(ano, mes, dia, c.HoraInicioDia, 0, 0, 0, c.Local)
+}
+
+// Dia retorna início e fim (exclusivo) do dia de uma data
+func (c Calendario) Dia(data time.Time) (time.Time, time.Time) {
+	return c.InicioDoDia(data.Year(), data.Month(), data.Day()), c.InicioDoDia(data.Year(), data.Month(), data.Day()+1)
+}
+
+// Mes retorna início e fim (exclusivo) do mês de uma data
+func (c Calendario) Mes(data time.Time) (time.Time, time.Time) {
+	return c.InicioDoDia(data.Year(), data.Month(), 1), c.InicioDoDia(data.Year(), data.Month()+1, 1)
+}
+
+// Semana retorna início e fim (exclusivo) da semana que começa em uma data
+func (c Calendario) Semana(inicioSemana time.Time) (time.Time, time.Time) {
+	return c.InicioDoDia(inicioSemana.Year(), inicioSemana.Month(), inicioSemana.Day()), c.InicioDoDia(inicioSemana.Year(), inicioSemana.Month(), inicioSemana.Day()+7)
+}
+
+// DiaDe retorna o dia (yyyy-mm-dd) do usuário ao qual um instante pertence
+func (c Calendario) DiaDe(instante time.Time) string {
+	local := instante.In(c.Local)
+	// Antes da hora de início o instante ainda pertence ao dia anterior
+	if local.Hour() < c.HoraInicioDia {
+		local = local.AddDate(0, 0, -1)
+	}
+	return local.Format("2006-01-02")
+}
+
+// Hoje retorna o dia (yyyy-mm-dd) atual do usuário
+func (c Calendario) Hoje() string {
+	return c.DiaDe(time.Now())
+}
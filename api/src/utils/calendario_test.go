@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func novoCalendarioTeste(t *testing.T, fusoHorario string, horaInicioDia int) Calendario {
+	t.Helper()
+	calendario, erro := NovoCalendario(fusoHorario, horaInicioDia)
+	if erro != nil {
+		t.Fatalf("NovoCalendario(%q, %d): %v", fusoHorario, horaInicioDia, erro)
+	}
+	return calendario
+}
+
+func instante(t *testing.T, texto string) time.Time {
+	t.Helper()
+	data, erro := time.Parse(time.RFC3339, texto)
+	if erro != nil {
+		t.Fatalf("time.Parse(%q): %v", texto, erro)
+	}
+	return data
+}
+
+func TestNovoCalendarioFusoInvalido(t *testing.T) {
+	if _, erro := NovoCalendario("America/Nao_Existe", 0); erro == nil {
+		t.Fatal("esperava erro para fuso horario inexistente")
+	}
+}
+
+func TestDiaDe(t *testing.T) {
+	casos := []struct {
+		nome          string
+		fusoHorario   string
+		horaInicioDia int
+		instante      string
+		esperado      string
+	}{
+		{"utc meia-noite", "UTC", 0, "2024-05-10T00:00:00Z", "2024-05-10"},
+		{"utc antes da meia-noite", "UTC", 0, "2024-05-09T23:59:59Z", "2024-05-09"},
+		{"fuso negativo muda o dia", "America/Sao_Paulo", 0, "2024-05-10T02:00:00Z", "2024-05-09"},
+		{"antes da hora de inicio pertence ao dia anterior", "America/Sao_Paulo", 4, "2024-05-10T06:59:59Z", "2024-05-09"},
+		{"na hora de inicio pertence ao proprio dia", "America/Sao_Paulo", 4, "2024-05-10T07:00:00Z", "2024-05-10"},
+		{"inicio do dia volta o mes", "UTC", 4, "2024-03-01T03:00:00Z", "2024-02-29"},
+		{"inicio do dia volta o ano", "UTC", 4, "2025-01-01T03:59:00Z", "2024-12-31"},
+		// Início do horário de verão em Nova York: 2h vira 3h em 10/03/2024, às 7h UTC
+		{"horario de verao antes do inicio do dia", "America/New_York", 4, "2024-03-10T07:30:00Z", "2024-03-09"},
+		{"horario de verao no inicio do dia", "America/New_York", 4, "2024-03-10T08:00:00Z", "2024-03-10"},
+		// Fim do horário de verão em Nova York: 2h volta para 1h em 03/11/2024, às 6h UTC
+		{"fim do horario de verao antes do inicio do dia", "America/New_York", 4, "2024-11-03T08:59:59Z", "2024-11-02"},
+		{"fim do horario de verao no inicio do dia", "America/New_York", 4, "2024-11-03T09:00:00Z", "2024-11-03"},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			calendario := novoCalendarioTeste(t, caso.fusoHorario, caso.horaInicioDia)
+			if dia := calendario.DiaDe(instante(t, caso.instante)); dia != caso.esperado {
+				t.Errorf("DiaDe(%s) = %s, esperado %s", caso.instante, dia, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestDia(t *testing.T) {
+	casos := []struct {
+		nome          string
+		fusoHorario   string
+		horaInicioDia int
+		data          string
+		inicio        string
+		fim           string
+	}{
+		{"utc", "UTC", 0, "2024-05-10T12:00:00Z", "2024-05-10T00:00:00Z", "2024-05-11T00:00:00Z"},
+		{"hora de inicio", "America/Sao_Paulo", 4, "2024-05-10T12:00:00Z", "2024-05-10T07:00:00Z", "2024-05-11T07:00:00Z"},
+		// Dias com troca de horário têm 23 ou 25 horas quando começam antes da troca
+		{"inicio do horario de verao", "America/New_York", 0, "2024-03-10T12:00:00Z", "2024-03-10T05:00:00Z", "2024-03-11T04:00:00Z"},
+		{"fim do horario de verao", "America/New_York", 0, "2024-11-03T12:00:00Z", "2024-11-03T04:00:00Z", "2024-11-04T05:00:00Z"},
+		{"inicio do horario de verao depois da troca", "America/New_York", 4, "2024-03-10T12:00:00Z", "2024-03-10T08:00:00Z", "2024-03-11T08:00:00Z"},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			calendario := novoCalendarioTeste(t, caso.fusoHorario, caso.horaInicioDia)
+			inicio, fim := calendario.Dia(instante(t, caso.data).In(calendario.Local))
+			if !inicio.Equal(instante(t, caso.inicio)) || !fim.Equal(instante(t, caso.fim)) {
+				t.Errorf("Dia(%s) = [%s, %s), esperado [%s, %s)", caso.data, inicio.UTC(), fim.UTC(), caso.inicio, caso.fim)
+			}
+		})
+	}
+}
+
+func TestSemana(t *testing.T) {
+	casos := []struct {
+		nome          string
+		fusoHorario   string
+		horaInicioDia int
+		inicioSemana  time.Time
+		inicio        string
+		fim           string
+	}{
+		{"utc", "UTC", 0, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), "2024-05-06T00:00:00Z", "2024-05-13T00:00:00Z"},
+		{"virada do mes", "UTC", 0, time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC), "2024-02-26T00:00:00Z", "2024-03-04T00:00:00Z"},
+		{"virada do ano", "America/Sao_Paulo", 0, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), "2024-12-30T03:00:00Z", "2025-01-06T03:00:00Z"},
+		{"hora de inicio", "America/Sao_Paulo", 4, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), "2024-05-06T07:00:00Z", "2024-05-13T07:00:00Z"},
+		{"horario de verao na semana", "America/New_York", 4, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), "2024-03-04T09:00:00Z", "2024-03-11T08:00:00Z"},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			calendario := novoCalendarioTeste(t, caso.fusoHorario, caso.horaInicioDia)
+			inicio, fim := calendario.Semana(caso.inicioSemana)
+			if !inicio.Equal(instante(t, caso.inicio)) || !fim.Equal(instante(t, caso.fim)) {
+				t.Errorf("Semana(%s) = [%s, %s), esperado [%s, %s)", caso.inicioSemana.Format("2006-01-02"), inicio.UTC(), fim.UTC(), caso.inicio, caso.fim)
+			}
+			// Os limites da semana devem cair exatamente nas viradas de dia do usuário
+			if dia := calendario.DiaDe(inicio); dia != caso.inicioSemana.Format("2006-01-02") {
+				t.Errorf("DiaDe(inicio) = %s, esperado %s", dia, caso.inicioSemana.Format("2006-01-02"))
+			}
+			if dia := calendario.DiaDe(fim.Add(-time.Nanosecond)); dia != caso.inicioSemana.AddDate(0, 0, 6).Format("2006-01-02") {
+				t.Errorf("DiaDe(fim - 1ns) = %s, esperado %s", dia, caso.inicioSemana.AddDate(0, 0, 6).Format("2006-01-02"))
+			}
+		})
+	}
+}
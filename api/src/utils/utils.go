@@ -1,11 +1,17 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
-// CalcularInicioDaSemana calcula o início de uma semana com base no ano e número da semana (ISO 8601)
+// CalcularInicioDaSemana calcula o início (segunda-feira) de uma semana com base no ano e número da semana.
+// A semana 1 começa na primeira segunda-feira do ano, o que difere da semana ISO 8601 quando o ano começa de terça a quinta
 func CalcularInicioDaSemana(ano, semana int) (time.Time, error) {
 	// Define uma data inicial no ano (1º de janeiro)
 	dataInicial := time.Date(ano, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -24,3 +30,73 @@ func CalcularInicioDaSemana(ano, semana int) (time.Time, error) {
 	}
 	return inicioSemana, nil
 }
+
+// CodificarCursor transforma data e id do último item de uma página em um cursor opaco para a próxima página
+func CodificarCursor(ultimo time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(ultimo.UTC().Format(time.RFC3339Nano) + "|" + id))
+}
+
+// DecodificarCursor recupera data e id guardados em um cursor de paginação
+func DecodificarCursor(cursor string) (time.Time, string, error) {
+	decodificado, erro := base64.RawURLEncoding.DecodeString(cursor)
+	if erro != nil {
+		return time.Time{}, "", errors.New("cursor invalido")
+	}
+	data, id, ok := strings.Cut(string(decodificado), "|")
+	if !ok || !EhUUID(id) {
+		return time.Time{}, "", errors.New("cursor invalido")
+	}
+	ultimo, erro := time.Parse(time.RFC3339Nano, data)
+	if erro != nil {
+		return time.Time{}, "", errors.New("cursor invalido")
+	}
+	return ultimo, id, nil
+}
+
+// GerarUUID gera um UUID versão 4 aleatório
+func GerarUUID() (string, error) {
+	var b [16]byte
+	if _, erro := rand.Read(b[:]); erro != nil {
+		return "", erro
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // versão 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variante RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// namespaceUUID é o namespace fixo da API (5ea288f3-3723-4310-8afd-e6bc767974a1) usado nos UUIDs gerados a partir de nomes
+var namespaceUUID = []byte{0x5e, 0xa2, 0x88, 0xf3, 0x37, 0x23, 0x43, 0x10, 0x8a, 0xfd, 0xe6, 0xbc, 0x76, 0x79, 0x74, 0xa1}
+
+// GerarUUIDDeNome gera sempre o mesmo UUID (versão 5, SHA-1 do namespace da API seguido do texto) para um mesmo texto, útil para reconhecer registros já importados
+func GerarUUIDDeNome(nome string) string {
+	hash := sha1.Sum(append(append([]byte{}, namespaceUUID...), nome...))
+	b := hash[:16]
+	b[6] = (b[6] & 0x0f) | 0x50 // versão 5
+	b[8] = (b[8] & 0x3f) | 0x80 // variante RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// EhUUID verifica se um texto está no formato de UUID (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx)
+func EhUUID(texto string) bool {
+	if len(texto) != 36 {
+		return false
+	}
+	for i, c := range texto {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ConverterFormatoData converte um formato de data como dd/MM/yyyy HH:mm:ss para o layout usado pelo pacote time
+func ConverterFormatoData(formato string) string {
+	return strings.NewReplacer("yyyy", "2006", "yy", "06", "MM", "01", "dd", "02", "HH", "15", "mm", "04", "ss", "05").Replace(formato)
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "testing"
+
+func TestGerarUUIDDeNome(t *testing.T) {
+	// Esperados calculados com o UUID versão 5 da RFC 4122 no namespace da API (uuid.uuid5 do Python)
+	casos := []struct {
+		nome     string
+		esperado string
+	}{
+		{"paciente|1", "d0658d05-eba7-57bd-9ce7-5fe013e24424"},
+		{"", "898e7777-d66b-50b4-8593-c604908afc76"},
+		{"apple|2024-01-01", "49efc67a-1424-5c55-8b71-839d1b4c886e"},
+	}
+	for _, caso := range casos {
+		primeiro := GerarUUIDDeNome(caso.nome)
+		if primeiro != caso.esperado {
+			t.Errorf("GerarUUIDDeNome(%q) = %s, esperado %s", caso.nome, primeiro, caso.esperado)
+		}
+		if segundo := GerarUUIDDeNome(caso.nome); segundo != primeiro {
+			t.Errorf("GerarUUIDDeNome(%q) mudou entre chamadas: %s e %s", caso.nome, primeiro, segundo)
+		}
+		if !EhUUID(primeiro) || primeiro[14] != '5' {
+			t.Errorf("GerarUUIDDeNome(%q) = %s, esperado UUID versao 5", caso.nome, primeiro)
+		}
+	}
+}
@@ -9,8 +9,48 @@ CREATE TABLE IF NOT EXISTS usuarios (
     data_nascimento DATE NOT NULL,
     senha VARCHAR(128) NOT NULL,
     data_criacao TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    fuso_horario VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    hora_inicio_dia SMALLINT NOT NULL DEFAULT 0 CHECK (hora_inicio_dia BETWEEN 0 AND 23),
+    limite_cafeina_mg INT DEFAULT 400,
+    limite_unidades_alcool NUMERIC(4,1),
+    altura SMALLINT CHECK (altura BETWEEN 50 AND 272),
+    peso NUMERIC(5,2) CHECK (peso BETWEEN 20 AND 500),
+    peso_atualizado_em TIMESTAMPTZ, -- instante do peso: data da medida de onde veio ou momento em que foi informado à mão
+    atividade_fisica CHAR(1) CHECK (atividade_fisica IN ('S', 'L', 'M', 'I')),
+    hora_acordar TIME,
+    hora_dormir TIME
 );
 
+-- Migração de bancos criados antes do fuso horário do usuário. Pode ser executada mais de uma vez
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS fuso_horario VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- Migração de bancos criados antes da hora de início do dia
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'usuarios' AND column_name = 'hora_inicio_dia') THEN
+        ALTER TABLE usuarios ADD COLUMN hora_inicio_dia SMALLINT NOT NULL DEFAULT 0 CHECK (hora_inicio_dia BETWEEN 0 AND 23);
+    END IF;
+END $$;
+
+-- Migração de bancos criados antes dos limites de cafeína e álcool
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS limite_cafeina_mg INT DEFAULT 400;
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS limite_unidades_alcool NUMERIC(4,1);
+
+-- Migração de bancos criados antes dos dados adicionais do perfil
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'usuarios' AND column_name = 'altura') THEN
+        ALTER TABLE usuarios ADD COLUMN altura SMALLINT CHECK (altura BETWEEN 50 AND 272),
+            ADD COLUMN peso NUMERIC(5,2) CHECK (peso BETWEEN 20 AND 500),
+            ADD COLUMN atividade_fisica CHAR(1) CHECK (atividade_fisica IN ('S', 'L', 'M', 'I')),
+            ADD COLUMN hora_acordar TIME,
+            ADD COLUMN hora_dormir TIME;
+    END IF;
+END $$;
+
+-- Migração de bancos criados antes de guardar o instante do peso
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS peso_atualizado_em TIMESTAMPTZ;
+
 CREATE TABLE IF NOT EXISTS lista_branca (
     usuario_matricula INT NOT NULL,
     token VARCHAR(255) NOT NULL,
@@ -19,10 +59,224 @@ CREATE TABLE IF NOT EXISTS lista_branca (
     FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE
 );
 
+-- Medidas corporais (kg e cm). A mais recente também atualiza o peso do usuário usado na recomendação de água, se não for anterior a ele
+CREATE TABLE IF NOT EXISTS medidas (
+    usuario_matricula INT NOT NULL,
+    data TIMESTAMPTZ NOT NULL,
+    peso NUMERIC(5,2) NOT NULL,
+    ombro NUMERIC(5,1) NOT NULL,
+    peito NUMERIC(5,1) NOT NULL,
+    braco NUMERIC(5,1) NOT NULL,
+    antebraco NUMERIC(5,1) NOT NULL,
+    cintura NUMERIC(5,1) NOT NULL,
+    quadril NUMERIC(5,1) NOT NULL,
+    coxa NUMERIC(5,1) NOT NULL,
+    panturrilha NUMERIC(5,1) NOT NULL,
+    PRIMARY KEY (usuario_matricula, data),
+    FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE
+);
+
+-- Catálogo de alimentos, valores por 100 g. Semeado pela API na inicialização a partir do CSV embutido
+CREATE TABLE IF NOT EXISTS alimentos (
+    id SERIAL PRIMARY KEY,
+    nome VARCHAR(100) UNIQUE NOT NULL,
+    kcal INT NOT NULL,
+    proteina NUMERIC(5,1) NOT NULL,
+    carboidrato NUMERIC(5,1) NOT NULL,
+    gordura NUMERIC(5,1) NOT NULL,
+    agua NUMERIC(5,1) NOT NULL -- gramas de água por 100 g, que contam como ml na hidratação
+);
+
+-- Cada consumo de alimentos é uma refeição em um instante, com os alimentos consumidos nela
+CREATE TABLE IF NOT EXISTS consumos_alimentos (
+    usuario_matricula INT NOT NULL,
+    data TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (usuario_matricula, data),
+    FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS alimentos_consumidos (
+    id SERIAL PRIMARY KEY,
+    usuario_matricula INT NOT NULL,
+    data TIMESTAMPTZ NOT NULL,
+    alimento_id INT NOT NULL,
+    quantidade NUMERIC(7,1) NOT NULL, -- gramas
+    FOREIGN KEY (usuario_matricula, data) REFERENCES consumos_alimentos(usuario_matricula, data) ON DELETE CASCADE,
+    FOREIGN KEY (alimento_id) REFERENCES alimentos(id)
+);
+
+CREATE INDEX IF NOT EXISTS alimentos_consumidos_usuario_data ON alimentos_consumidos (usuario_matricula, data);
+
+CREATE TABLE IF NOT EXISTS bebidas (
+    id SERIAL PRIMARY KEY,
+    nome VARCHAR(30) NOT NULL,
+    tipo VARCHAR(15) NOT NULL,
+    fator_hidratacao NUMERIC(3,2) NOT NULL,
+    usuario_matricula INT,
+    FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE
+);
+
+INSERT INTO bebidas (id, nome, tipo, fator_hidratacao) VALUES
+    (1, 'Água', 'agua', 1.00),
+    (2, 'Café', 'cafe', 0.80),
+    (3, 'Chá', 'cha', 0.90),
+    (4, 'Leite', 'leite', 1.00),
+    (5, 'Suco', 'suco', 0.90),
+    (6, 'Isotônico', 'isotonico', 1.00),
+    (7, 'Bebida alcoólica', 'alcool', 0.00)
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval('bebidas_id_seq', GREATEST((SELECT MAX(id) FROM bebidas), 7));
+
+CREATE TABLE IF NOT EXISTS recipientes (
+    id SERIAL PRIMARY KEY,
+    usuario_matricula INT NOT NULL,
+    nome VARCHAR(30) NOT NULL,
+    capacidade INT NOT NULL,
+    FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS historico_de_agua (
+    id UUID PRIMARY KEY,
     usuario_matricula INT NOT NULL,
-    data_consumo TIMESTAMP NOT NULL,
+    data_consumo TIMESTAMPTZ NOT NULL,
     quantidade INT NOT NULL,
-    PRIMARY KEY (usuario_matricula, data_consumo),
+    bebida_id INT NOT NULL DEFAULT 1,
+    cafeina_mg INT NOT NULL DEFAULT 0,
+    unidades_alcool NUMERIC(4,1) NOT NULL DEFAULT 0,
+    recipiente_id INT,
+    origem VARCHAR(20), -- aplicativo de onde o consumo foi importado (apple_health, google_fit)
+    FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE,
+    FOREIGN KEY (bebida_id) REFERENCES bebidas(id),
+    FOREIGN KEY (recipiente_id) REFERENCES recipientes(id) ON DELETE SET NULL
+);
+
+-- Migração de bancos criados antes do id, do fuso horário e das colunas de bebida, cafeína, álcool, recipiente e origem.
+-- Datas antigas sem fuso foram gravadas em UTC. Pode ser executada mais de uma vez
+ALTER TABLE historico_de_agua ADD COLUMN IF NOT EXISTS id UUID;
+UPDATE historico_de_agua SET id = gen_random_uuid() WHERE id IS NULL;
+ALTER TABLE historico_de_agua ADD COLUMN IF NOT EXISTS bebida_id INT NOT NULL DEFAULT 1;
+ALTER TABLE historico_de_agua ADD COLUMN IF NOT EXISTS cafeina_mg INT NOT NULL DEFAULT 0;
+ALTER TABLE historico_de_agua ADD COLUMN IF NOT EXISTS unidades_alcool NUMERIC(4,1) NOT NULL DEFAULT 0;
+ALTER TABLE historico_de_agua ADD COLUMN IF NOT EXISTS recipiente_id INT;
+ALTER TABLE historico_de_agua ADD COLUMN IF NOT EXISTS origem VARCHAR(20);
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'historico_de_agua' AND column_name = 'data_consumo' AND data_type = 'timestamp without time zone') THEN
+        ALTER TABLE historico_de_agua ALTER COLUMN data_consumo TYPE TIMESTAMPTZ USING data_consumo AT TIME ZONE 'UTC';
+    END IF;
+    -- A chave primária antiga era (usuario_matricula, data_consumo)
+    IF NOT EXISTS (SELECT 1 FROM pg_index i JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+        WHERE i.indrelid = 'historico_de_agua'::regclass AND i.indisprimary AND a.attname = 'id') THEN
+        ALTER TABLE historico_de_agua DROP CONSTRAINT IF EXISTS historico_de_agua_pkey;
+        ALTER TABLE historico_de_agua ADD PRIMARY KEY (id);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'historico_de_agua_bebida_id_fkey') THEN
+        ALTER TABLE historico_de_agua ADD CONSTRAINT historico_de_agua_bebida_id_fkey FOREIGN KEY (bebida_id) REFERENCES bebidas(id);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'historico_de_agua_recipiente_id_fkey') THEN
+        ALTER TABLE historico_de_agua ADD CONSTRAINT historico_de_agua_recipiente_id_fkey FOREIGN KEY (recipiente_id) REFERENCES recipientes(id) ON DELETE SET NULL;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS historico_de_agua_usuario_data ON historico_de_agua (usuario_matricula, data_consumo, id);
+
+CREATE TABLE IF NOT EXISTS metas_de_agua (
+    usuario_matricula INT NOT NULL,
+    valida_desde DATE NOT NULL,
+    agua_meta INT NOT NULL,
+    PRIMARY KEY (usuario_matricula, valida_desde),
+    FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE
+);
+
+-- Migração de bancos com a meta única em usuarios.agua_meta: a meta passa a valer desde o cadastro e a coluna é removida
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'usuarios' AND column_name = 'agua_meta') THEN
+        INSERT INTO metas_de_agua (usuario_matricula, valida_desde, agua_meta)
+        SELECT matricula, COALESCE(data_criacao::DATE, CURRENT_DATE), agua_meta FROM usuarios WHERE agua_meta > 0
+        ON CONFLICT (usuario_matricula, valida_desde) DO NOTHING;
+        ALTER TABLE usuarios DROP COLUMN agua_meta;
+    END IF;
+END $$;
+
+-- Resumo diário de hidratação usado nas sequências de meta e nas conquistas, atualizado a cada alteração no histórico de água
+CREATE TABLE IF NOT EXISTS dias_meta_agua (
+    usuario_matricula INT NOT NULL,
+    dia DATE NOT NULL, -- dia no fuso horário e hora de início do dia do usuário
+    hidratacao INT NOT NULL,
+    agua_meta INT NOT NULL,
+    atingida BOOLEAN GENERATED ALWAYS AS (agua_meta > 0 AND hidratacao >= agua_meta) STORED,
+    consumos INT NOT NULL DEFAULT 0, -- consumos de bebidas no dia, usados nas conquistas
+    quantidade INT NOT NULL DEFAULT 0, -- ml de bebidas no dia, sem o fator de hidratação
+    consumo_manha BOOLEAN NOT NULL DEFAULT FALSE, -- se houve consumo entre 5h e 12h no horário local
+    PRIMARY KEY (usuario_matricula, dia),
     FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE
-);
\ No newline at end of file
+);
+
+-- Migração: métricas das conquistas passam a vir do resumo diário, preenchidas a partir do histórico existente
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'dias_meta_agua' AND column_name = 'consumos') THEN
+        ALTER TABLE dias_meta_agua ADD COLUMN consumos INT NOT NULL DEFAULT 0;
+        ALTER TABLE dias_meta_agua ADD COLUMN quantidade INT NOT NULL DEFAULT 0;
+        ALTER TABLE dias_meta_agua ADD COLUMN consumo_manha BOOLEAN NOT NULL DEFAULT FALSE;
+        UPDATE dias_meta_agua d SET consumos = r.consumos, quantidade = r.quantidade, consumo_manha = r.consumo_manha
+        FROM (
+            SELECT h.usuario_matricula, ((h.data_consumo AT TIME ZONE u.fuso_horario) - make_interval(hours => u.hora_inicio_dia))::DATE AS dia,
+            COUNT(*) AS consumos, SUM(h.quantidade) AS quantidade,
+            BOOL_OR(EXTRACT(HOUR FROM h.data_consumo AT TIME ZONE u.fuso_horario) BETWEEN 5 AND 11) AS consumo_manha
+            FROM historico_de_agua h JOIN usuarios u ON u.matricula = h.usuario_matricula
+            GROUP BY 1, 2
+        ) r
+        WHERE d.usuario_matricula = r.usuario_matricula AND d.dia = r.dia;
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS sequencias_agua (
+    usuario_matricula INT PRIMARY KEY,
+    inicio_ultima DATE, -- primeiro dia da sequência mais recente de metas atingidas
+    fim_ultima DATE,
+    maior INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE
+);
+
+-- Regras das conquistas: cada uma é obtida quando a métrica do usuário chega ao limite. Novas conquistas são só novas linhas
+CREATE TABLE IF NOT EXISTS conquistas (
+    id VARCHAR(50) PRIMARY KEY,
+    nome VARCHAR(50) NOT NULL,
+    descricao VARCHAR(200) NOT NULL,
+    metrica VARCHAR(30) NOT NULL CHECK (metrica IN ('consumos_total', 'litros_total', 'maior_sequencia', 'dias_meta_atingida', 'dias_consumo_manha')),
+    limite NUMERIC(10,2) NOT NULL,
+    ativa BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+INSERT INTO conquistas (id, nome, descricao, metrica, limite) VALUES
+    ('primeiro_consumo', 'Primeiro gole', 'Registrou o primeiro consumo de água', 'consumos_total', 1),
+    ('sequencia_7_dias', 'Semana hidratada', 'Atingiu a meta de água 7 dias seguidos', 'maior_sequencia', 7),
+    ('sequencia_30_dias', 'Mês hidratado', 'Atingiu a meta de água 30 dias seguidos', 'maior_sequencia', 30),
+    ('100_litros', 'Cem litros', 'Bebeu 100 litros desde o cadastro', 'litros_total', 100),
+    ('1000_litros', 'Mil litros', 'Bebeu 1000 litros desde o cadastro', 'litros_total', 1000),
+    ('30_manhas', 'Madrugador', 'Bebeu água pela manhã (5h às 12h) em 30 dias diferentes', 'dias_consumo_manha', 30),
+    ('meta_100_dias', 'Constância', 'Atingiu a meta de água em 100 dias', 'dias_meta_atingida', 100)
+ON CONFLICT (id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS conquistas_usuarios (
+    usuario_matricula INT NOT NULL,
+    conquista_id VARCHAR(50) NOT NULL,
+    conquistada_em TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (usuario_matricula, conquista_id),
+    FOREIGN KEY (usuario_matricula) REFERENCES usuarios(matricula) ON DELETE CASCADE,
+    FOREIGN KEY (conquista_id) REFERENCES conquistas(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS chaves_idempotencia (
+    usuario_matricula INT NOT NULL, -- 0 em rotas sem usuário logado (cadastro), com a chave guardada como HMAC da chave e da requisição
+    chave VARCHAR(255) NOT NULL,
+    hash_requisicao CHAR(64) NOT NULL,
+    status_code INT, -- nulo enquanto a primeira requisição ainda está sendo processada
+    corpo BYTEA,
+    criada_em TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (usuario_matricula, chave)
+);